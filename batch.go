@@ -0,0 +1,129 @@
+package errnie
+
+import (
+	"iter"
+)
+
+/*
+Partition splits a batch into its successful values and its failures, both
+keyed by item index so a batch can be reconciled against its input:
+
+	batch := errnie.PartitionSeq(slices.Values(rows), importRow)
+
+	for position, value := range batch.Values {
+		markImported(rows[batch.Indexes[position]], value)
+	}
+
+	for index, err := range batch.Failed {
+		markRejected(rows[index], err)
+	}
+
+	log.Printf("%d invalid rows", batch.Failures.Count(errnie.Validation))
+	return batch.Failures.Err()
+*/
+type Partition[T any] struct {
+	// Values holds the successes in item order.
+	Values []T
+	// Indexes holds the item index of each entry in Values.
+	Indexes []int
+	// Failed maps the item index of each failure to its error.
+	Failed map[int]error
+	// Failures aggregates the same failures, each wrapped with its item index
+	// in the field "index", with per-Kind counts and Err.
+	Failures *Collector
+}
+
+/*
+PartitionSeq runs fn on each item of items and partitions the outcomes. The
+items are processed in order on the calling goroutine.
+*/
+func PartitionSeq[T, U any](items iter.Seq[T], fn func(T) (U, error)) Partition[U] {
+	return partition(Results(items, fn))
+}
+
+/*
+PartitionSeq2 is PartitionSeq for key-value sequences such as maps.All. Each
+failure also carries the item's key in the field "key".
+*/
+func PartitionSeq2[K, V, U any](items iter.Seq2[K, V], fn func(K, V) (U, error)) Partition[U] {
+	return partition(Results2(items, fn))
+}
+
+/*
+Results runs fn lazily on each item of items and yields the item index with
+its Result, so memory stays flat however long the sequence is. A failure keeps
+its Kind and carries the index in the field "index". Stopping the range loop
+stops processing; fn is never called for the remaining items.
+
+	for index, result := range errnie.Results(lines, parseLine) {
+		if result.Err() != nil {
+			continue
+		}
+		emit(index, result.Value())
+	}
+*/
+func Results[T, U any](items iter.Seq[T], fn func(T) (U, error)) iter.Seq2[int, Result[U]] {
+	return func(yield func(int, Result[U]) bool) {
+		index := 0
+
+		for item := range items {
+			value, err := fn(item)
+
+			if err != nil {
+				err = indexedError(err, index)
+			}
+
+			if !yield(index, Result[U]{value: value, err: err}) {
+				return
+			}
+
+			index++
+		}
+	}
+}
+
+/*
+Results2 is Results for key-value sequences. It yields each item's key with
+its Result, and a failure carries both the field "index" and the field "key".
+*/
+func Results2[K, V, U any](items iter.Seq2[K, V], fn func(K, V) (U, error)) iter.Seq2[K, Result[U]] {
+	return func(yield func(K, Result[U]) bool) {
+		index := 0
+
+		for key, item := range items {
+			value, err := fn(key, item)
+
+			if err != nil {
+				err = indexedError(err, index).With("key", key)
+			}
+
+			if !yield(key, Result[U]{value: value, err: err}) {
+				return
+			}
+
+			index++
+		}
+	}
+}
+
+/*
+partition drains results into a Partition.
+*/
+func partition[K, T any](results iter.Seq2[K, Result[T]]) Partition[T] {
+	batch := Partition[T]{Failed: map[int]error{}, Failures: &Collector{}}
+	index := 0
+
+	for _, result := range results {
+		if result.err != nil {
+			batch.Failed[index] = result.err
+			batch.Failures.Add(result.err)
+		} else {
+			batch.Values = append(batch.Values, result.value)
+			batch.Indexes = append(batch.Indexes, index)
+		}
+
+		index++
+	}
+
+	return batch
+}
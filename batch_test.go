@@ -0,0 +1,171 @@
+package errnie
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkBatchSink int
+
+/*
+parseTestItem parses s, failing with Validation on anything but an integer.
+*/
+func parseTestItem(s string) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, Err(Validation, "not a number", err)
+	}
+
+	return value, nil
+}
+
+/*
+TestPartitionSeq verifies that a batch splits into values and indexed failures.
+*/
+func TestPartitionSeq(t *testing.T) {
+	Convey("Given items where some fail to parse", t, func() {
+		items := []string{"1", "x", "3", "y", "5"}
+
+		Convey("When they are partitioned", func() {
+			batch := PartitionSeq(slices.Values(items), parseTestItem)
+			failures := batch.Failures.Errors()
+
+			Convey("Then the successes should be kept in item order with their indexes", func() {
+				So(batch.Values, ShouldResemble, []int{1, 3, 5})
+				So(batch.Indexes, ShouldResemble, []int{0, 2, 4})
+			})
+
+			Convey("Then the failures should be looked up by item index", func() {
+				So(batch.Failed, ShouldHaveLength, 2)
+				So(batch.Failed[1], ShouldEqual, failures[0])
+				So(batch.Failed[3], ShouldEqual, failures[1])
+				So(batch.Failed[0], ShouldBeNil)
+			})
+
+			Convey("Then each failure should carry its item index and Kind", func() {
+				So(failures, ShouldHaveLength, 2)
+				So(failures[0].Error(), ShouldEqual, "not a number index=1")
+				So(failures[1].Error(), ShouldEqual, "not a number index=3")
+				So(batch.Failures.Count(Validation), ShouldEqual, 2)
+				So(IsValidation(batch.Failures.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given items that all succeed", t, func() {
+		Convey("When they are partitioned", func() {
+			batch := PartitionSeq(slices.Values([]string{"7"}), parseTestItem)
+
+			Convey("Then there should be no failures", func() {
+				So(batch.Values, ShouldResemble, []int{7})
+				So(batch.Failures.Len(), ShouldEqual, 0)
+				So(batch.Failures.Err(), ShouldBeNil)
+			})
+		})
+	})
+}
+
+/*
+TestPartitionSeq2 verifies that key-value batches keep the key on failures.
+*/
+func TestPartitionSeq2(t *testing.T) {
+	Convey("Given a map with one bad entry", t, func() {
+		items := map[string]string{"bad": "x"}
+
+		Convey("When it is partitioned", func() {
+			batch := PartitionSeq2(maps.All(items), func(_ string, s string) (int, error) {
+				return parseTestItem(s)
+			})
+
+			target, _ := AsErrnie(batch.Failures.Err())
+
+			Convey("Then the failure should carry both the index and the key", func() {
+				So(batch.Values, ShouldBeEmpty)
+				So(target.Fields(), ShouldResemble, []any{"index", 0, "key", "bad"})
+			})
+		})
+	})
+}
+
+/*
+TestResults verifies lazy evaluation and early termination of streamed results.
+*/
+func TestResults(t *testing.T) {
+	Convey("Given a stream of items", t, func() {
+		var calls int
+
+		fn := func(s string) (int, error) {
+			calls++
+			return parseTestItem(s)
+		}
+
+		items := slices.Values([]string{"1", "x", "3", "4"})
+
+		Convey("When the consumer stops after the third result", func() {
+			var indexes []int
+			var failed []error
+
+			for index, result := range Results(items, fn) {
+				indexes = append(indexes, index)
+
+				if result.Err() != nil {
+					failed = append(failed, result.Err())
+				}
+
+				if index == 2 {
+					break
+				}
+			}
+
+			Convey("Then fn should not run for the remaining items", func() {
+				So(calls, ShouldEqual, 3)
+				So(indexes, ShouldResemble, []int{0, 1, 2})
+			})
+
+			Convey("Then the failure should be indexed and unwrap to its cause", func() {
+				var numErr *strconv.NumError
+
+				So(failed, ShouldHaveLength, 1)
+				So(failed[0].Error(), ShouldEqual, "not a number index=1")
+				So(errors.As(failed[0], &numErr), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a key-value stream", t, func() {
+		items := slices.All([]string{"2", "z"})
+
+		Convey("When it is streamed with Results2", func() {
+			keys := []int{}
+			var values []int
+
+			for key, result := range Results2(items, func(_ int, s string) (int, error) { return parseTestItem(s) }) {
+				keys = append(keys, key)
+				values = append(values, result.ValueOr(-1))
+			}
+
+			Convey("Then each result should come with its key", func() {
+				So(keys, ShouldResemble, []int{0, 1})
+				So(values, ShouldResemble, []int{2, -1})
+			})
+		})
+	})
+}
+
+/*
+BenchmarkResults measures streaming a successful batch.
+*/
+func BenchmarkResults(b *testing.B) {
+	items := slices.Repeat([]string{"42"}, 64)
+
+	for range b.N {
+		for _, result := range Results(slices.Values(items), parseTestItem) {
+			benchmarkBatchSink = result.Value()
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package errnie
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+BreakerState is the state of one Op's circuit.
+*/
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+/*
+String returns the state name used in fields and log lines.
+*/
+func (state BreakerState) String() string {
+	switch state {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+BreakerConfig configures a Breaker. Zero values fall back to five consecutive
+failures, a 30 second open period, and a single half-open probe.
+*/
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive counted failures that
+	// trips a closed circuit.
+	FailureThreshold int
+	// OpenTimeout is how long a circuit fails fast before probing again.
+	OpenTimeout time.Duration
+	// HalfOpenProbes bounds the concurrent calls let through while probing.
+	HalfOpenProbes int
+	// Counts decides whether an error counts against the circuit. Defaults to
+	// CountsAsInfrastructure.
+	Counts func(error) bool
+	// OnStateChange observes transitions. Defaults to logging through Warn.
+	OnStateChange func(op string, from, to BreakerState)
+}
+
+/*
+Breaker is a circuit breaker that tracks one circuit per ErrnieError Op name.
+Only infrastructure failures count against a circuit, so a burst of Validation
+or NotFound errors never trips it. An open circuit fails fast with a
+ServiceUnavailable ErrnieError that carries the breaker state as fields.
+*/
+type Breaker struct {
+	config   BreakerConfig
+	mutex    sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+/*
+circuit is the per-Op breaker state guarded by Breaker.mutex.
+*/
+type circuit struct {
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+/*
+breakerTransition is a state change reported to OnStateChange after the
+breaker lock is released.
+*/
+type breakerTransition struct {
+	op       string
+	from, to BreakerState
+}
+
+/*
+NewBreaker creates a Breaker from config, applying defaults for zero fields.
+*/
+func NewBreaker(config BreakerConfig) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+
+	if config.Counts == nil {
+		config.Counts = CountsAsInfrastructure
+	}
+
+	if config.OnStateChange == nil {
+		config.OnStateChange = logBreakerTransition
+	}
+
+	return &Breaker{
+		config:   config,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+/*
+CountsAsInfrastructure reports whether err signals a failing dependency rather
+than a bad request: its Kind is registered as retryable or maps to a 5xx
+status other than 501, since a NotImplemented answer is deterministic. Caller
+cancellation does not count.
+*/
+func CountsAsInfrastructure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	info, ok := LookupKind(kindOf(err))
+	if !ok {
+		return true
+	}
+
+	return info.Retryable || (info.HTTPStatus >= 500 && info.HTTPStatus != http.StatusNotImplemented)
+}
+
+/*
+Do runs fn through the circuit for op. When the circuit is open it returns the
+fail-fast error without calling fn; otherwise fn's error is recorded and
+returned unchanged. A panic in fn is recorded as a counted failure, releasing
+its half-open probe, and then continues unwinding.
+*/
+func (breaker *Breaker) Do(op string, fn func() error) error {
+	if err := breaker.Allow(op); err != nil {
+		return err
+	}
+
+	recorded := false
+	defer breaker.recordAbort(op, &recorded)
+
+	err := fn()
+	breaker.Record(op, err)
+	recorded = true
+
+	return err
+}
+
+/*
+BreakerDoes is the Does counterpart of Breaker.Do for functions that return a
+value.
+*/
+func BreakerDoes[T any](breaker *Breaker, op string, fn func() (T, error)) Result[T] {
+	if err := breaker.Allow(op); err != nil {
+		var zero T
+
+		return Result[T]{value: zero, err: err}
+	}
+
+	recorded := false
+	defer breaker.recordAbort(op, &recorded)
+
+	value, err := fn()
+	breaker.Record(op, err)
+	recorded = true
+
+	return Result[T]{value: value, err: err}
+}
+
+/*
+Allow reserves a call on op's circuit. It returns nil when the call may
+proceed, in which case the caller must report the outcome with Record, or the
+fail-fast ServiceUnavailable error when the circuit is open.
+*/
+func (breaker *Breaker) Allow(op string) error {
+	breaker.mutex.Lock()
+
+	state := breaker.circuit(op)
+	now := breaker.now()
+
+	var transition *breakerTransition
+
+	if state.state == BreakerOpen && now.Sub(state.openedAt) >= breaker.config.OpenTimeout {
+		transition = state.moveTo(op, BreakerHalfOpen)
+	}
+
+	var err error
+
+	switch state.state {
+	case BreakerOpen:
+		err = breaker.openError(op, state, now)
+	case BreakerHalfOpen:
+		if state.probes >= breaker.config.HalfOpenProbes {
+			err = breaker.openError(op, state, now)
+		} else {
+			state.probes++
+		}
+	}
+
+	breaker.mutex.Unlock()
+	breaker.notify(transition)
+
+	return err
+}
+
+/*
+Record reports the outcome of a call admitted by Allow. Errors rejected by
+Counts are treated as successes because the dependency answered.
+*/
+func (breaker *Breaker) Record(op string, err error) {
+	breaker.record(op, breaker.config.Counts(err))
+}
+
+/*
+recordAbort records a call whose fn panicked or exited the goroutine before
+Record ran, as a counted failure. Do and BreakerDoes defer it.
+*/
+func (breaker *Breaker) recordAbort(op string, recorded *bool) {
+	if !*recorded {
+		breaker.record(op, true)
+	}
+}
+
+/*
+record applies a call outcome to op's circuit.
+*/
+func (breaker *Breaker) record(op string, counted bool) {
+	breaker.mutex.Lock()
+
+	state := breaker.circuit(op)
+
+	var transition *breakerTransition
+
+	switch state.state {
+	case BreakerHalfOpen:
+		state.probes = max(state.probes-1, 0)
+
+		if counted {
+			state.openedAt = breaker.now()
+			transition = state.moveTo(op, BreakerOpen)
+		} else {
+			state.failures = 0
+			transition = state.moveTo(op, BreakerClosed)
+		}
+	case BreakerClosed:
+		if !counted {
+			state.failures = 0
+			break
+		}
+
+		state.failures++
+
+		if state.failures >= breaker.config.FailureThreshold {
+			state.openedAt = breaker.now()
+			transition = state.moveTo(op, BreakerOpen)
+		}
+	}
+
+	breaker.mutex.Unlock()
+	breaker.notify(transition)
+}
+
+/*
+State returns the current state of op's circuit.
+*/
+func (breaker *Breaker) State(op string) BreakerState {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if state, ok := breaker.circuits[op]; ok {
+		return state.state
+	}
+
+	return BreakerClosed
+}
+
+/*
+circuit returns op's circuit, creating a closed one on first use. The caller
+must hold breaker.mutex.
+*/
+func (breaker *Breaker) circuit(op string) *circuit {
+	state, ok := breaker.circuits[op]
+
+	if !ok {
+		state = &circuit{}
+		breaker.circuits[op] = state
+	}
+
+	return state
+}
+
+/*
+openError builds the fail-fast error for an open or saturated circuit.
+*/
+func (breaker *Breaker) openError(op string, state *circuit, now time.Time) *ErrnieError {
+	retryAfter := max(breaker.config.OpenTimeout-now.Sub(state.openedAt), 0)
+
+	return Err(ServiceUnavailable, "circuit breaker is open", nil).Operation(op).With(
+		"breaker_state", state.state.String(),
+		"failures", state.failures,
+		"retry_after", retryAfter,
+	)
+}
+
+/*
+notify reports a transition to the configured hook outside the lock.
+*/
+func (breaker *Breaker) notify(transition *breakerTransition) {
+	if transition != nil {
+		breaker.config.OnStateChange(transition.op, transition.from, transition.to)
+	}
+}
+
+/*
+moveTo changes the circuit state and describes the transition.
+*/
+func (state *circuit) moveTo(op string, to BreakerState) *breakerTransition {
+	from := state.state
+	state.state = to
+
+	if to == BreakerHalfOpen {
+		state.probes = 0
+	}
+
+	return &breakerTransition{op: op, from: from, to: to}
+}
+
+/*
+logBreakerTransition is the default OnStateChange hook.
+*/
+func logBreakerTransition(op string, from, to BreakerState) {
+	Warn("circuit breaker state changed", "op", op, "from", from.String(), "to", to.String())
+}
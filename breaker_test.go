@@ -0,0 +1,270 @@
+package errnie
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+newTestBreaker returns a breaker with a controllable clock and a transition log.
+*/
+func newTestBreaker(config BreakerConfig) (*Breaker, *time.Time, *[]string) {
+	clock := time.Unix(1700000000, 0)
+	transitions := []string{}
+
+	config.OnStateChange = func(op string, from, to BreakerState) {
+		transitions = append(transitions, op+":"+from.String()+"->"+to.String())
+	}
+
+	breaker := NewBreaker(config)
+	breaker.now = func() time.Time { return clock }
+
+	return breaker, &clock, &transitions
+}
+
+/*
+TestBreaker verifies tripping, fail-fast errors, and half-open probing.
+*/
+func TestBreaker(t *testing.T) {
+	failing := func() error { return Err(ServiceUnavailable, "db down", nil) }
+	succeeding := func() error { return nil }
+
+	Convey("Given a closed breaker with a threshold of two", t, func() {
+		breaker, clock, transitions := newTestBreaker(BreakerConfig{
+			FailureThreshold: 2,
+			OpenTimeout:      time.Second,
+		})
+
+		Convey("When infrastructure failures reach the threshold", func() {
+			breaker.Do("user.load", failing)
+			breaker.Do("user.load", failing)
+
+			Convey("Then the circuit should open and fail fast", func() {
+				called := false
+				err := breaker.Do("user.load", func() error {
+					called = true
+					return nil
+				})
+				target, _ := AsErrnie(err)
+
+				So(called, ShouldBeFalse)
+				So(breaker.State("user.load"), ShouldEqual, BreakerOpen)
+				So(IsServiceUnavailable(err), ShouldBeTrue)
+				So(target.Op, ShouldEqual, "user.load")
+				So(target.Fields(), ShouldResemble, []any{
+					"breaker_state", "open", "failures", 2, "retry_after", time.Second,
+				})
+				So(*transitions, ShouldResemble, []string{"user.load:closed->open"})
+			})
+
+			Convey("Then other Ops should be unaffected", func() {
+				So(breaker.State("user.save"), ShouldEqual, BreakerClosed)
+				So(breaker.Do("user.save", succeeding), ShouldBeNil)
+			})
+
+			Convey("Then a successful probe after the timeout should close it", func() {
+				*clock = clock.Add(time.Second)
+
+				So(breaker.Do("user.load", succeeding), ShouldBeNil)
+				So(breaker.State("user.load"), ShouldEqual, BreakerClosed)
+				So(*transitions, ShouldResemble, []string{
+					"user.load:closed->open",
+					"user.load:open->half_open",
+					"user.load:half_open->closed",
+				})
+			})
+
+			Convey("Then a failed probe should reopen it", func() {
+				*clock = clock.Add(time.Second)
+
+				So(IsServiceUnavailable(breaker.Do("user.load", failing)), ShouldBeTrue)
+				So(breaker.State("user.load"), ShouldEqual, BreakerOpen)
+			})
+
+			Convey("Then a panicking probe should reopen it and release its slot", func() {
+				*clock = clock.Add(time.Second)
+
+				So(func() { breaker.Do("user.load", func() error { panic("nil pool") }) }, ShouldPanicWith, "nil pool")
+				So(breaker.State("user.load"), ShouldEqual, BreakerOpen)
+
+				*clock = clock.Add(time.Second)
+
+				So(breaker.Do("user.load", succeeding), ShouldBeNil)
+				So(breaker.State("user.load"), ShouldEqual, BreakerClosed)
+			})
+
+			Convey("Then only HalfOpenProbes calls should be admitted while probing", func() {
+				*clock = clock.Add(time.Second)
+
+				So(breaker.Allow("user.load"), ShouldBeNil)
+
+				err := breaker.Allow("user.load")
+				target, _ := AsErrnie(err)
+
+				So(IsServiceUnavailable(err), ShouldBeTrue)
+				So(target.Fields()[1], ShouldEqual, "half_open")
+			})
+		})
+
+		Convey("When only client errors occur", func() {
+			for range 5 {
+				breaker.Do("user.load", func() error { return Err(Validation, "bad id", nil) })
+				breaker.Do("user.load", func() error { return Err(NotFound, "missing", nil) })
+			}
+
+			Convey("Then the circuit should stay closed", func() {
+				So(breaker.State("user.load"), ShouldEqual, BreakerClosed)
+			})
+		})
+
+		Convey("When a success interrupts failures", func() {
+			breaker.Do("user.load", failing)
+			breaker.Do("user.load", succeeding)
+			breaker.Do("user.load", failing)
+
+			Convey("Then consecutive counting should restart", func() {
+				So(breaker.State("user.load"), ShouldEqual, BreakerClosed)
+			})
+		})
+	})
+
+	Convey("Given BreakerDoes with an open circuit", t, func() {
+		breaker, _, _ := newTestBreaker(BreakerConfig{FailureThreshold: 1})
+		breaker.Do("price.fetch", failing)
+
+		Convey("When BreakerDoes is called", func() {
+			result := BreakerDoes(breaker, "price.fetch", func() (int, error) { return 42, nil })
+
+			Convey("Then it should fail fast with the zero value", func() {
+				So(result.Value(), ShouldEqual, 0)
+				So(IsServiceUnavailable(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given BreakerDoes with a function that panics", t, func() {
+		breaker, _, _ := newTestBreaker(BreakerConfig{FailureThreshold: 1})
+
+		Convey("When it is called", func() {
+			panicking := func() { BreakerDoes(breaker, "price.fetch", func() (int, error) { panic("nil client") }) }
+
+			Convey("Then the panic should propagate and count as a failure", func() {
+				So(panicking, ShouldPanicWith, "nil client")
+				So(breaker.State("price.fetch"), ShouldEqual, BreakerOpen)
+			})
+		})
+	})
+
+	Convey("Given the default state change hook", t, func() {
+		buffer := configureTestLogger(t, log.WarnLevel)
+		breaker := NewBreaker(BreakerConfig{FailureThreshold: 1})
+
+		Convey("When the circuit trips", func() {
+			breaker.Do("mail.send", failing)
+
+			Convey("Then the transition should be logged through Warn", func() {
+				So(buffer.String(), ShouldContainSubstring, "circuit breaker state changed")
+				So(buffer.String(), ShouldContainSubstring, "mail.send")
+			})
+		})
+	})
+
+	Convey("Given concurrent calls on one breaker", t, func() {
+		breaker := NewBreaker(BreakerConfig{FailureThreshold: 1000, OnStateChange: func(string, BreakerState, BreakerState) {}})
+
+		Convey("When many goroutines record outcomes", func() {
+			var wg sync.WaitGroup
+
+			for index := range 50 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					if index%2 == 0 {
+						breaker.Do("shared", failing)
+						return
+					}
+
+					breaker.Do("shared", succeeding)
+				}()
+			}
+			wg.Wait()
+
+			Convey("Then the breaker should remain consistent", func() {
+				So(breaker.State("shared"), ShouldEqual, BreakerClosed)
+			})
+		})
+	})
+}
+
+/*
+TestCountsAsInfrastructure verifies which Kinds count against a circuit.
+*/
+func TestCountsAsInfrastructure(t *testing.T) {
+	Convey("Given an error of each built-in Kind", t, func() {
+		counted := map[Kind]bool{
+			Internal:             true,
+			BadRequest:           false,
+			Validation:           false,
+			Unauthorized:         false,
+			Forbidden:            false,
+			NotFound:             false,
+			MethodNotAllowed:     false,
+			NotAcceptable:        false,
+			Timeout:              true,
+			DeadlineExceeded:     true,
+			Canceled:             false,
+			Conflict:             false,
+			PreconditionFailed:   false,
+			UnsupportedMedia:     false,
+			ExpectationFailed:    false,
+			UnprocessableContent: false,
+			TooManyRequests:      true,
+			NotImplemented:       false,
+			BadGateway:           true,
+			ServiceUnavailable:   true,
+			Unknown:              true,
+			IO:                   true,
+			EOF:                  true,
+		}
+
+		Convey("When CountsAsInfrastructure is called", func() {
+			Convey("Then only dependency failures should count", func() {
+				for kind, expected := range counted {
+					So(CountsAsInfrastructure(Err(kind, "failed", nil)), ShouldEqual, expected)
+				}
+			})
+		})
+	})
+
+	Convey("Given errors of several Kinds", t, func() {
+		Convey("When CountsAsInfrastructure is called", func() {
+			Convey("Then only dependency failures should count", func() {
+				So(CountsAsInfrastructure(Err(Internal, "boom", nil)), ShouldBeTrue)
+				So(CountsAsInfrastructure(Err(Timeout, "slow", nil)), ShouldBeTrue)
+				So(CountsAsInfrastructure(errors.New("plain")), ShouldBeTrue)
+				So(CountsAsInfrastructure(Err(Validation, "bad", nil)), ShouldBeFalse)
+				So(CountsAsInfrastructure(Err(NotFound, "missing", nil)), ShouldBeFalse)
+				So(CountsAsInfrastructure(Canceled), ShouldBeFalse)
+				So(CountsAsInfrastructure(nil), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkBreakerDo measures a closed-circuit call.
+*/
+func BenchmarkBreakerDo(b *testing.B) {
+	breaker := NewBreaker(BreakerConfig{})
+	fn := func() error { return nil }
+
+	for b.Loop() {
+		benchmarkErrnieErrorSink = breaker.Do("bench", fn)
+	}
+}
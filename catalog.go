@@ -0,0 +1,465 @@
+package errnie
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+DefaultLocale is the fallback locale used until SetCatalog names another.
+*/
+const DefaultLocale = "en"
+
+/*
+Catalog resolves message keys to templates per locale. Templates reference
+the alternating key/value arguments of ErrKey by name, for example
+"user {id} not found". Implementations must be safe for concurrent use.
+*/
+type Catalog interface {
+	// Message returns the template for key in exactly locale.
+	Message(locale, key string) (string, bool)
+	// Locales lists the locales that have at least one message.
+	Locales() []string
+}
+
+/*
+MapCatalog is an in-memory Catalog keyed by locale and message key. Locale
+names are normalized to lower case with "-" separators, so "de_CH" and
+"de-ch" are the same locale.
+*/
+type MapCatalog struct {
+	messages map[string]map[string]string
+}
+
+/*
+catalogState is the active catalog and its fallback locale.
+*/
+type catalogState struct {
+	catalog  Catalog
+	fallback string
+}
+
+var activeCatalog atomic.Pointer[catalogState]
+
+/*
+localeKey is the context key for WithLocale.
+*/
+type localeKey struct{}
+
+/*
+NewMapCatalog builds a MapCatalog from locale → key → template maps.
+*/
+func NewMapCatalog(messages map[string]map[string]string) *MapCatalog {
+	catalog := &MapCatalog{messages: make(map[string]map[string]string, len(messages))}
+
+	for locale, templates := range messages {
+		catalog.add(locale, templates)
+	}
+
+	return catalog
+}
+
+/*
+Message returns the template for key in locale.
+*/
+func (catalog *MapCatalog) Message(locale, key string) (string, bool) {
+	template, ok := catalog.messages[normalizeLocale(locale)][key]
+
+	return template, ok
+}
+
+/*
+Locales returns the catalog's locales in sorted order.
+*/
+func (catalog *MapCatalog) Locales() []string {
+	locales := make([]string, 0, len(catalog.messages))
+
+	for locale := range catalog.messages {
+		locales = append(locales, locale)
+	}
+
+	slices.Sort(locales)
+
+	return locales
+}
+
+/*
+add merges templates into locale, later files overriding earlier keys.
+*/
+func (catalog *MapCatalog) add(locale string, templates map[string]string) {
+	locale = normalizeLocale(locale)
+
+	if catalog.messages[locale] == nil {
+		catalog.messages[locale] = make(map[string]string, len(templates))
+	}
+
+	for key, template := range templates {
+		catalog.messages[locale][key] = template
+	}
+}
+
+/*
+LoadJSONCatalog loads every file in fsys matching pattern (see fs.Glob). Each
+file holds one locale, named by the file's base name without extension, as a
+flat JSON object of key → template:
+
+	// locales/de.json
+	{"user.not_found": "Benutzer {id} wurde nicht gefunden"}
+
+	catalog, err := errnie.LoadJSONCatalog(localeFiles, "locales/*.json")
+*/
+func LoadJSONCatalog(fsys fs.FS, pattern string) (*MapCatalog, error) {
+	return loadCatalog(fsys, pattern, func(data []byte) (map[string]string, error) {
+		var templates map[string]string
+
+		if err := json.Unmarshal(data, &templates); err != nil {
+			return nil, err
+		}
+
+		return templates, nil
+	})
+}
+
+/*
+LoadPOCatalog loads gettext-style .po files from fsys matching pattern, one
+locale per file named like LoadJSONCatalog. msgid is the message key and
+msgstr the template. Comments, msgctxt, and plural forms beyond msgstr[0] are
+ignored, as are entries with an empty msgid or msgstr.
+
+	msgid "user.not_found"
+	msgstr "Benutzer {id} wurde nicht gefunden"
+*/
+func LoadPOCatalog(fsys fs.FS, pattern string) (*MapCatalog, error) {
+	return loadCatalog(fsys, pattern, parsePO)
+}
+
+/*
+loadCatalog reads the files matching pattern and merges them by locale.
+*/
+func loadCatalog(
+	fsys fs.FS, pattern string, parse func([]byte) (map[string]string, error),
+) (*MapCatalog, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, Err(Validation, "invalid catalog pattern", err).Operation("catalog.load").With("pattern", pattern)
+	}
+
+	if len(names) == 0 {
+		return nil, Err(NotFound, "no catalog files match pattern", nil).Operation("catalog.load").With("pattern", pattern)
+	}
+
+	catalog := NewMapCatalog(nil)
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, Err(IO, "read catalog file", err).Operation("catalog.load").With("file", name)
+		}
+
+		templates, err := parse(data)
+		if err != nil {
+			return nil, Err(Validation, "parse catalog file", err).Operation("catalog.load").With("file", name)
+		}
+
+		base := path.Base(name)
+		catalog.add(strings.TrimSuffix(base, path.Ext(base)), templates)
+	}
+
+	return catalog, nil
+}
+
+/*
+parsePO extracts msgid/msgstr pairs from a .po file.
+*/
+func parsePO(data []byte) (map[string]string, error) {
+	templates := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var (
+		msgid, msgstr string
+		target        *string
+		skip          bool
+		lineNumber    int
+	)
+
+	flush := func() {
+		if msgid != "" && msgstr != "" {
+			templates[msgid] = msgstr
+		}
+
+		msgid, msgstr, target, skip = "", "", nil, false
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keyword, quoted, _ := strings.Cut(line, " ")
+
+		if strings.HasPrefix(line, `"`) {
+			keyword, quoted = "", line
+		}
+
+		value, err := strconv.Unquote(strings.TrimSpace(quoted))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		switch keyword {
+		case "msgctxt":
+			flush()
+			target = nil
+		case "msgid":
+			if msgid != "" || msgstr != "" {
+				flush()
+			}
+
+			msgid, target = value, &msgid
+		case "msgstr", "msgstr[0]":
+			msgstr, target, skip = value, &msgstr, false
+		case "":
+			if target != nil && !skip {
+				*target += value
+			}
+		default:
+			skip = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+
+	return templates, nil
+}
+
+/*
+SetCatalog installs the catalog used by ErrKey and Localize and the locale to
+fall back to when a requested locale has no message. An empty fallback means
+DefaultLocale; a nil catalog disables resolution.
+*/
+func SetCatalog(catalog Catalog, fallback string) {
+	if catalog == nil {
+		activeCatalog.Store(nil)
+		return
+	}
+
+	if fallback == "" {
+		fallback = DefaultLocale
+	}
+
+	activeCatalog.Store(&catalogState{catalog: catalog, fallback: normalizeLocale(fallback)})
+}
+
+/*
+ErrKey constructs an ErrnieError whose message comes from the catalog. key and
+the alternating key/value args stay on the error as MessageKey and MessageArgs
+for logging and for Localize. Message is resolved in the fallback locale, or
+set to key when no catalog has it, but Error, %+v, JSON, and slog resolve the
+key again when they render, so a package-level ErrKey created before SetCatalog
+or kept across a catalog swap shows the current message.
+*/
+func ErrKey(kind Kind, key string, cause error, args ...any) *ErrnieError {
+	err := newErr(kind, key, cause, 2)
+	err.MessageKey = key
+	err.MessageArgs = args
+
+	if message, ok := resolveMessage("", key, args); ok {
+		err.Message = message
+	}
+
+	return err
+}
+
+/*
+WithLocale returns a context carrying locale for Localize.
+*/
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+/*
+LocaleFrom returns the locale stored by WithLocale, or "".
+*/
+func LocaleFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	locale, _ := ctx.Value(localeKey{}).(string)
+
+	return locale
+}
+
+/*
+Localize renders err's message in the locale carried by ctx. It resolves the
+MessageKey of the first ErrnieError in the chain, trying the exact locale, its
+base language ("de" for "de-CH"), and then the fallback locale. Without a key
+or a matching message it returns Message; errors that are not ErrnieErrors
+return their Error text.
+*/
+func Localize(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	target, ok := AsErrnie(err)
+	if !ok {
+		return err.Error()
+	}
+
+	if target.MessageKey != "" {
+		if message, ok := resolveMessage(LocaleFrom(ctx), target.MessageKey, target.MessageArgs); ok {
+			return message
+		}
+	}
+
+	if target.Message != "" {
+		return target.Message
+	}
+
+	return target.Error()
+}
+
+/*
+SupportsLocale reports whether the active catalog has messages for locale or
+its base language.
+*/
+func SupportsLocale(locale string) bool {
+	state := activeCatalog.Load()
+	if state == nil || locale == "" {
+		return false
+	}
+
+	locales := state.catalog.Locales()
+
+	for _, candidate := range localeChain(locale) {
+		for _, available := range locales {
+			if normalizeLocale(available) == candidate {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/*
+resolveMessage looks key up along the locale chain of locale and then of the
+fallback locale, and formats the first template found with args.
+*/
+func resolveMessage(locale, key string, args []any) (string, bool) {
+	state := activeCatalog.Load()
+	if state == nil {
+		return "", false
+	}
+
+	for _, candidate := range append(localeChain(locale), localeChain(state.fallback)...) {
+		if template, ok := state.catalog.Message(candidate, key); ok {
+			return formatMessage(template, args), true
+		}
+	}
+
+	return "", false
+}
+
+/*
+localeChain returns locale and its parent locales, most specific first:
+"de-CH-1996" yields "de-ch-1996", "de-ch", "de".
+*/
+func localeChain(locale string) []string {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		return nil
+	}
+
+	chain := []string{locale}
+
+	for {
+		index := strings.LastIndexByte(locale, '-')
+		if index <= 0 {
+			return chain
+		}
+
+		locale = locale[:index]
+		chain = append(chain, locale)
+	}
+}
+
+/*
+normalizeLocale lower-cases locale and uses "-" as the separator.
+*/
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+/*
+formatMessage replaces {name} placeholders in template with the matching
+value from the alternating key/value args, redacted under name by the active
+RedactionPolicy like any field. Unknown placeholders are kept.
+*/
+func formatMessage(template string, args []any) string {
+	if len(args) == 0 || !strings.Contains(template, "{") {
+		return template
+	}
+
+	var builder strings.Builder
+	policy := redactionPolicySnapshot()
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		builder.WriteString(template[:start])
+
+		name := template[start+1 : start+end]
+		placeholder := template[start : start+end+1]
+		template = template[start+end+1:]
+
+		if value, ok := messageArg(args, name); ok {
+			value, _ = policy.redact(name, value)
+			fmt.Fprint(&builder, value)
+		} else {
+			builder.WriteString(placeholder)
+		}
+	}
+
+	builder.WriteString(template)
+
+	return builder.String()
+}
+
+/*
+messageArg returns the value for name in alternating key/value args.
+*/
+func messageArg(args []any, name string) (any, bool) {
+	for index := 0; index+1 < len(args); index += 2 {
+		if key, ok := args[index].(string); ok && key == name {
+			return args[index+1], true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,289 @@
+package errnie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkLocalizeSink string
+
+/*
+setTestCatalog installs catalog for the duration of a test.
+*/
+func setTestCatalog(t testing.TB, catalog Catalog, fallback string) {
+	t.Helper()
+
+	previous := activeCatalog.Load()
+	SetCatalog(catalog, fallback)
+
+	t.Cleanup(func() {
+		activeCatalog.Store(previous)
+	})
+}
+
+/*
+testCatalogFiles holds the same messages as JSON and gettext files.
+*/
+var testCatalogFiles = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{"user.not_found": "user {id} not found", "quota.exceeded": "quota of {limit} exceeded"}`)},
+	"locales/de.json": {Data: []byte(`{"user.not_found": "Benutzer {id} wurde nicht gefunden"}`)},
+	"locales/de_CH.po": {Data: []byte(`# Swiss German
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#. shown when the user lookup misses
+msgctxt "errors"
+msgid "user.not_found"
+msgstr ""
+"Benutzer {id} "
+"existiert nicht"
+
+msgid "item.count"
+msgid_plural "item.count.plural"
+msgstr[0] "ein Artikel"
+msgstr[1] "{count} Artikel"
+
+msgid "untranslated"
+msgstr ""
+`)},
+	"broken/en.po": {Data: []byte("msgid user.not_found\n")},
+}
+
+/*
+TestLoadCatalog verifies the JSON and gettext catalog loaders.
+*/
+func TestLoadCatalog(t *testing.T) {
+	Convey("Given JSON catalog files", t, func() {
+		Convey("When they are loaded", func() {
+			catalog, err := LoadJSONCatalog(testCatalogFiles, "locales/*.json")
+
+			Convey("Then each file should become a locale", func() {
+				So(err, ShouldBeNil)
+				So(catalog.Locales(), ShouldResemble, []string{"de", "en"})
+
+				template, ok := catalog.Message("DE", "user.not_found")
+				So(ok, ShouldBeTrue)
+				So(template, ShouldEqual, "Benutzer {id} wurde nicht gefunden")
+			})
+		})
+	})
+
+	Convey("Given a gettext catalog file", t, func() {
+		Convey("When it is loaded", func() {
+			catalog, err := LoadPOCatalog(testCatalogFiles, "locales/*.po")
+
+			Convey("Then msgid and msgstr pairs should be read", func() {
+				So(err, ShouldBeNil)
+				So(catalog.Locales(), ShouldResemble, []string{"de-ch"})
+
+				template, _ := catalog.Message("de_CH", "user.not_found")
+				So(template, ShouldEqual, "Benutzer {id} existiert nicht")
+
+				plural, _ := catalog.Message("de-ch", "item.count")
+				So(plural, ShouldEqual, "ein Artikel")
+
+				_, ok := catalog.Message("de-ch", "untranslated")
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given patterns that fail", t, func() {
+		Convey("When nothing matches or a file is malformed", func() {
+			_, missing := LoadJSONCatalog(testCatalogFiles, "missing/*.json")
+			_, broken := LoadPOCatalog(testCatalogFiles, "broken/*.po")
+
+			Convey("Then typed errors should be returned", func() {
+				So(IsNotFound(missing), ShouldBeTrue)
+				So(IsValidation(broken), ShouldBeTrue)
+				So(broken.Error(), ShouldContainSubstring, "file=broken/en.po")
+			})
+		})
+	})
+}
+
+/*
+TestErrKey verifies catalog-backed messages and per-locale rendering.
+*/
+func TestErrKey(t *testing.T) {
+	Convey("Given a catalog with English, German, and Swiss German messages", t, func() {
+		catalog, _ := LoadJSONCatalog(testCatalogFiles, "locales/*.json")
+		swiss, _ := LoadPOCatalog(testCatalogFiles, "locales/*.po")
+
+		for _, locale := range swiss.Locales() {
+			template, _ := swiss.Message(locale, "user.not_found")
+			catalog.add(locale, map[string]string{"user.not_found": template})
+		}
+
+		setTestCatalog(t, catalog, "en")
+
+		err := ErrKey(NotFound, "user.not_found", nil, "id", 42).Operation("user.load")
+
+		Convey("When the error is built", func() {
+			Convey("Then Message should be resolved in the fallback locale", func() {
+				So(err.Message, ShouldEqual, "user 42 not found")
+				So(err.MessageKey, ShouldEqual, "user.not_found")
+				So(err.MessageArgs, ShouldResemble, []any{"id", 42})
+				So(err.Error(), ShouldEqual, "user.load: user 42 not found")
+			})
+		})
+
+		Convey("When it is localized per request", func() {
+			Convey("Then the locale chain should be followed", func() {
+				So(Localize(WithLocale(context.Background(), "de"), err), ShouldEqual, "Benutzer 42 wurde nicht gefunden")
+				So(Localize(WithLocale(context.Background(), "de-CH"), err), ShouldEqual, "Benutzer 42 existiert nicht")
+				So(Localize(WithLocale(context.Background(), "de-AT"), err), ShouldEqual, "Benutzer 42 wurde nicht gefunden")
+				So(Localize(WithLocale(context.Background(), "fr"), err), ShouldEqual, "user 42 not found")
+				So(Localize(context.Background(), err), ShouldEqual, "user 42 not found")
+			})
+		})
+
+		Convey("When a key has no translation in the requested locale", func() {
+			quota := ErrKey(TooManyRequests, "quota.exceeded", nil, "limit", 100, "unused", true)
+
+			Convey("Then the fallback locale should be used", func() {
+				So(Localize(WithLocale(context.Background(), "de"), quota), ShouldEqual, "quota of 100 exceeded")
+			})
+		})
+
+		Convey("When the key is unknown or the error is wrapped", func() {
+			unknown := ErrKey(Internal, "missing.key", nil)
+
+			Convey("Then the key and the chain should still resolve sensibly", func() {
+				So(unknown.Message, ShouldEqual, "missing.key")
+				So(Localize(context.Background(), unknown), ShouldEqual, "missing.key")
+				So(Localize(WithLocale(context.Background(), "de"), Combine(err, unknown)), ShouldEqual, "Benutzer 42 wurde nicht gefunden")
+				So(Localize(context.Background(), Err(IO, "disk", nil)), ShouldEqual, "disk")
+				So(Localize(context.Background(), nil), ShouldEqual, "")
+			})
+		})
+
+		Convey("When supported locales are queried", func() {
+			Convey("Then base languages should match regional tags", func() {
+				So(SupportsLocale("de-DE"), ShouldBeTrue)
+				So(SupportsLocale("EN"), ShouldBeTrue)
+				So(SupportsLocale("fr"), ShouldBeFalse)
+			})
+		})
+
+		Convey("When the error is logged", func() {
+			buffer := configureTestLogger(t, log.ErrorLevel)
+			Error(err)
+
+			Convey("Then the key and args should be logged", func() {
+				So(buffer.String(), ShouldContainSubstring, `"message_key":"user.not_found"`)
+				So(buffer.String(), ShouldContainSubstring, `"message_args":{"id":42}`)
+			})
+		})
+
+		Convey("When the error crosses the JSON wire format", func() {
+			encoded, _ := json.Marshal(err)
+			decoded, decodeErr := UnmarshalError(encoded)
+			target, _ := AsErrnie(decoded)
+
+			Convey("Then the key and args should survive for the consumer", func() {
+				So(decodeErr, ShouldBeNil)
+				So(target.MessageKey, ShouldEqual, "user.not_found")
+				So(Localize(WithLocale(context.Background(), "de"), decoded), ShouldEqual, "Benutzer 42 wurde nicht gefunden")
+			})
+		})
+
+		Convey("When keyed errors with different args are fingerprinted", func() {
+			Convey("Then they should group by key", func() {
+				So(Fingerprint(ErrKey(NotFound, "user.not_found", nil, "id", "bob")), ShouldEqual,
+					Fingerprint(ErrKey(NotFound, "user.not_found", nil, "id", "alice")))
+			})
+		})
+	})
+
+	Convey("Given no catalog", t, func() {
+		setTestCatalog(t, nil, "")
+
+		Convey("When ErrKey is called", func() {
+			err := ErrKey(Validation, "email.invalid", nil, "email", "x")
+
+			Convey("Then the key should serve as the message", func() {
+				So(err.Message, ShouldEqual, "email.invalid")
+				So(Localize(WithLocale(context.Background(), "de"), err), ShouldEqual, "email.invalid")
+				So(SupportsLocale("en"), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a keyed error created before any catalog is installed", t, func() {
+		setTestCatalog(t, nil, "")
+
+		err := ErrKey(NotFound, "user.not_found", nil, "id", 42)
+		before := err.Error()
+
+		Convey("When a catalog is installed and later swapped", func() {
+			catalog, _ := LoadJSONCatalog(testCatalogFiles, "locales/*.json")
+
+			setTestCatalog(t, catalog, "en")
+			installed := err.Error()
+			encoded, _ := json.Marshal(err)
+
+			setTestCatalog(t, catalog, "de")
+			swapped := err.Error()
+
+			Convey("Then each render should resolve the key against the active catalog", func() {
+				So(before, ShouldEqual, "user.not_found")
+				So(installed, ShouldEqual, "user 42 not found")
+				So(string(encoded), ShouldContainSubstring, `"message":"user 42 not found"`)
+				So(swapped, ShouldEqual, "Benutzer 42 wurde nicht gefunden")
+				So(fmt.Sprintf("%+v", err), ShouldContainSubstring, "[not_found] Benutzer 42 wurde nicht gefunden")
+			})
+		})
+	})
+
+	Convey("Given a redaction policy and a template that names a denied arg", t, func() {
+		catalog := NewMapCatalog(map[string]map[string]string{
+			"en": {"token.rejected": "token {token} rejected"},
+		})
+		setTestCatalog(t, catalog, "en")
+		setTestRedactionPolicy(t, RedactionPolicy{Keys: []string{"token"}})
+
+		Convey("When the keyed error is rendered and localized", func() {
+			err := ErrKey(Unauthorized, "token.rejected", nil, "token", "abc")
+			encoded, _ := json.Marshal(err)
+
+			Convey("Then the arg should be masked in every rendering", func() {
+				So(err.Error(), ShouldEqual, "token [REDACTED] rejected")
+				So(Localize(context.Background(), err), ShouldEqual, "token [REDACTED] rejected")
+				So(string(encoded), ShouldNotContainSubstring, "abc")
+			})
+		})
+	})
+
+	Convey("Given templates with unknown and unterminated placeholders", t, func() {
+		Convey("When they are formatted", func() {
+			Convey("Then only known names should be substituted", func() {
+				So(formatMessage("{a} and {b} and {", []any{"a", 1}), ShouldEqual, "1 and {b} and {")
+				So(formatMessage("no args {a}", nil), ShouldEqual, "no args {a}")
+			})
+		})
+	})
+}
+
+/*
+BenchmarkLocalize measures per-request message resolution.
+*/
+func BenchmarkLocalize(b *testing.B) {
+	catalog, _ := LoadJSONCatalog(testCatalogFiles, "locales/*.json")
+	setTestCatalog(b, catalog, "en")
+
+	err := ErrKey(NotFound, "user.not_found", nil, "id", 42)
+	ctx := WithLocale(context.Background(), "de-CH")
+
+	b.ResetTimer()
+	for range b.N {
+		benchmarkLocalizeSink = Localize(ctx, err)
+	}
+}
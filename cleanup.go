@@ -0,0 +1,77 @@
+package errnie
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"slices"
+)
+
+/*
+Close closes closer and merges a failure into *target through Combine, so a
+deferred close no longer loses its error:
+
+	func write(path string, data []byte) (err error) {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer errnie.Close(&err, file, "config.write")
+
+		_, err = file.Write(data)
+		return err
+	}
+
+The failure is wrapped as an IO ErrnieError with Op op, unless one of the
+ignore predicates (such as IgnoreClosed) matches it. A nil closer is skipped,
+and a nil target logs the error through Error instead.
+*/
+func Close(target *error, closer io.Closer, op string, ignore ...func(error) bool) {
+	if closer == nil {
+		return
+	}
+
+	mergeCleanup(target, closer.Close(), "close failed", op, ignore)
+}
+
+/*
+Cleanup runs fn and merges a failure into *target like Close, for cleanup
+that is not an io.Closer, such as flushing a writer or rolling back a
+transaction:
+
+	defer errnie.Cleanup(&err, writer.Flush)
+*/
+func Cleanup(target *error, fn func() error, ignore ...func(error) bool) {
+	if fn == nil {
+		return
+	}
+
+	mergeCleanup(target, fn(), "cleanup failed", "", ignore)
+}
+
+/*
+IgnoreClosed reports whether err says the resource was already closed, for use
+as a Close or Cleanup predicate when a resource may be closed twice.
+*/
+func IgnoreClosed(err error) bool {
+	return errors.Is(err, fs.ErrClosed) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe)
+}
+
+/*
+mergeCleanup wraps a cleanup failure and joins it into *target.
+*/
+func mergeCleanup(target *error, err error, message, op string, ignore []func(error) bool) {
+	if err == nil || slices.ContainsFunc(ignore, func(predicate func(error) bool) bool { return predicate(err) }) {
+		return
+	}
+
+	wrapped := newErr(IO, message, err, 3).Operation(op)
+
+	if target == nil {
+		Error(wrapped)
+		return
+	}
+
+	*target = Combine(*target, wrapped)
+}
@@ -0,0 +1,178 @@
+package errnie
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkCleanupSink error
+
+/*
+testCloser is an io.Closer that returns a fixed error.
+*/
+type testCloser struct {
+	err error
+}
+
+/*
+Close returns the configured error.
+*/
+func (closer testCloser) Close() error {
+	return closer.err
+}
+
+/*
+benchmarkCleanupFn is a named successful cleanup for BenchmarkClose.
+*/
+func benchmarkCleanupFn() error {
+	return nil
+}
+
+/*
+TestClose verifies that deferred close failures reach the returned error.
+*/
+func TestClose(t *testing.T) {
+	Convey("Given a function whose deferred close fails after success", t, func() {
+		enableTestStackTraces(t)
+		closeErr := errors.New("disk full")
+
+		Convey("When it returns", func() {
+			err := func() (err error) {
+				defer Close(&err, testCloser{err: closeErr}, "config.write")
+				return nil
+			}()
+
+			target, _ := AsErrnie(err)
+
+			Convey("Then the close failure should be an IO error with the op", func() {
+				So(IsIO(err), ShouldBeTrue)
+				So(errors.Is(err, closeErr), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "config.write: close failed")
+				So(target.StackTrace()[0].Function, ShouldContainSubstring, "TestClose")
+			})
+		})
+	})
+
+	Convey("Given a function that fails and whose close fails too", t, func() {
+		writeErr := Err(Validation, "bad payload", nil)
+
+		Convey("When it returns", func() {
+			err := func() (err error) {
+				defer Close(&err, testCloser{err: errors.New("disk full")}, "config.write")
+				return writeErr
+			}()
+
+			Convey("Then both failures should be joined", func() {
+				So(errors.Is(err, writeErr), ShouldBeTrue)
+				So(IsIO(err), ShouldBeTrue)
+				So(IsValidation(err), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a file that is closed twice", t, func() {
+		file, openErr := os.Create(filepath.Join(t.TempDir(), "twice"))
+		So(openErr, ShouldBeNil)
+		So(file.Close(), ShouldBeNil)
+
+		Convey("When the second close is ignored with IgnoreClosed", func() {
+			ignored := func() (err error) {
+				defer Close(&err, file, "file.close", IgnoreClosed)
+				return nil
+			}()
+
+			reported := func() (err error) {
+				defer Close(&err, file, "file.close")
+				return nil
+			}()
+
+			Convey("Then only the unfiltered call should report it", func() {
+				So(ignored, ShouldBeNil)
+				So(errors.Is(reported, fs.ErrClosed), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a nil closer and a nil target", t, func() {
+		buffer := configureTestLogger(t, log.ErrorLevel)
+
+		Convey("When Close is called", func() {
+			func() {
+				var err error
+				defer Close(&err, nil, "nothing")
+				defer Close(nil, testCloser{err: errors.New("socket reset")}, "conn.close")
+			}()
+
+			Convey("Then the nil closer should be skipped and the untargeted failure logged", func() {
+				So(buffer.String(), ShouldContainSubstring, "conn.close: close failed")
+				So(buffer.String(), ShouldNotContainSubstring, "nothing")
+			})
+		})
+	})
+}
+
+/*
+TestCleanup verifies deferred cleanup functions that are not closers.
+*/
+func TestCleanup(t *testing.T) {
+	Convey("Given a deferred flush that fails", t, func() {
+		flushErr := errors.New("short write")
+
+		Convey("When the function returns", func() {
+			err := func() (err error) {
+				defer Cleanup(&err, func() error { return flushErr })
+				return nil
+			}()
+
+			Convey("Then the failure should be an IO cleanup error", func() {
+				So(IsIO(err), ShouldBeTrue)
+				So(errors.Is(err, flushErr), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "cleanup failed")
+			})
+		})
+
+		Convey("When a predicate ignores it", func() {
+			err := func() (err error) {
+				defer Cleanup(&err, func() error { return flushErr }, func(err error) bool {
+					return errors.Is(err, flushErr)
+				})
+				return nil
+			}()
+
+			Convey("Then the returned error should be untouched", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkClose measures the deferred helpers on the success path.
+*/
+func BenchmarkClose(b *testing.B) {
+	closer := &testCloser{}
+
+	b.Run("close", func(b *testing.B) {
+		for range b.N {
+			benchmarkCleanupSink = func() (err error) {
+				defer Close(&err, closer, "bench.close")
+				return nil
+			}()
+		}
+	})
+
+	b.Run("cleanup", func(b *testing.B) {
+		for range b.N {
+			benchmarkCleanupSink = func() (err error) {
+				defer Cleanup(&err, benchmarkCleanupFn, IgnoreClosed)
+				return nil
+			}()
+		}
+	})
+}
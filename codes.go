@@ -0,0 +1,217 @@
+package errnie
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Code is a stable, machine-readable identifier for one specific failure, such as
+"USER_EMAIL_TAKEN". Kind says how to treat an error; Code says exactly which
+error it is, so clients can branch on it without parsing messages.
+*/
+type Code string
+
+/*
+CodeInfo binds a Code to its Kind, default message, and documentation.
+*/
+type CodeInfo struct {
+	// Code is the identifier. RegisterCode sets it.
+	Code Code
+	// Kind classifies every error carrying the code.
+	Kind Kind
+	// Message is the default message for ErrCode.
+	Message string
+	// Docs is a URL or short text explaining the code to API consumers.
+	Docs string
+}
+
+/*
+codeRegistry is an immutable snapshot of registered codes. Writers copy it;
+readers load it with a single atomic read.
+*/
+type codeRegistry struct {
+	infos map[Code]CodeInfo
+}
+
+var (
+	codeRegistrySnapshot atomic.Pointer[codeRegistry]
+	codeRegistryMutex    sync.Mutex
+)
+
+/*
+RegisterCode declares a Code and returns it. Call it from a package-level var
+so duplicates are caught when the program starts:
+
+	var UserEmailTaken = errnie.RegisterCode("USER_EMAIL_TAKEN", errnie.CodeInfo{
+		Kind:    errnie.Conflict,
+		Message: "email address is already registered",
+		Docs:    "https://docs.example.com/errors#USER_EMAIL_TAKEN",
+	})
+
+RegisterCode panics when code is empty or already registered, or when info has
+no Kind.
+*/
+func RegisterCode(code Code, info CodeInfo) Code {
+	code = Code(strings.TrimSpace(string(code)))
+
+	if code == "" {
+		panic("errnie: RegisterCode called with an empty code")
+	}
+
+	if info.Kind == nil {
+		panic("errnie: RegisterCode " + string(code) + ": kind is required")
+	}
+
+	info.Code = code
+
+	codeRegistryMutex.Lock()
+	defer codeRegistryMutex.Unlock()
+
+	infos := make(map[Code]CodeInfo)
+
+	if current := codeRegistrySnapshot.Load(); current != nil {
+		if _, exists := current.infos[code]; exists {
+			panic("errnie: code " + string(code) + " registered twice")
+		}
+
+		infos = maps.Clone(current.infos)
+	}
+
+	infos[code] = info
+	codeRegistrySnapshot.Store(&codeRegistry{infos: infos})
+
+	return code
+}
+
+/*
+LookupCode returns the registered metadata for code.
+*/
+func LookupCode(code Code) (CodeInfo, bool) {
+	current := codeRegistrySnapshot.Load()
+	if current == nil {
+		return CodeInfo{}, false
+	}
+
+	info, ok := current.infos[code]
+
+	return info, ok
+}
+
+/*
+Codes returns every registered code sorted by name, for generating error
+documentation.
+*/
+func Codes() []CodeInfo {
+	current := codeRegistrySnapshot.Load()
+	if current == nil {
+		return nil
+	}
+
+	infos := slices.Collect(maps.Values(current.infos))
+
+	slices.SortFunc(infos, func(left, right CodeInfo) int {
+		return strings.Compare(string(left.Code), string(right.Code))
+	})
+
+	return infos
+}
+
+/*
+ErrCode constructs an ErrnieError for a registered code, taking Kind and
+Message from its CodeInfo. An unregistered code yields an Unknown error whose
+message is the code itself.
+*/
+func ErrCode(code Code, cause error) *ErrnieError {
+	info, ok := LookupCode(code)
+	if !ok {
+		info = CodeInfo{Kind: Unknown, Message: string(code)}
+	}
+
+	err := newErr(info.Kind, info.Message, cause, 2)
+	err.Code = code
+
+	return err
+}
+
+/*
+IsCode reports whether any ErrnieError in err's chain, including causes and
+joined branches, carries code.
+*/
+func IsCode(err error, code Code) bool {
+	return code != "" && hasCode(err, code)
+}
+
+/*
+CodeOf returns the first non-empty Code in err's chain, or "".
+*/
+func CodeOf(err error) Code {
+	for err != nil {
+		if target, ok := err.(*ErrnieError); ok {
+			if target == nil {
+				return ""
+			}
+
+			if target.Code != "" {
+				return target.Code
+			}
+
+			err = target.Cause
+
+			continue
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, child := range joined.Unwrap() {
+				if code := CodeOf(child); code != "" {
+					return code
+				}
+			}
+
+			return ""
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return ""
+}
+
+/*
+hasCode walks every branch of err's chain looking for code.
+*/
+func hasCode(err error, code Code) bool {
+	for err != nil {
+		if target, ok := err.(*ErrnieError); ok {
+			if target == nil {
+				return false
+			}
+
+			if target.Code == code {
+				return true
+			}
+
+			err = target.Cause
+
+			continue
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, child := range joined.Unwrap() {
+				if hasCode(child, code) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return false
+}
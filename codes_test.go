@@ -0,0 +1,175 @@
+package errnie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkIsCodeSink bool
+
+/*
+testEmailTaken and testQuotaExhausted are registered once for the package's
+tests; the registry rejects duplicates.
+*/
+var (
+	testEmailTaken = RegisterCode("TEST_EMAIL_TAKEN", CodeInfo{
+		Kind:    Conflict,
+		Message: "email address is already registered",
+		Docs:    "https://docs.example.com/errors#TEST_EMAIL_TAKEN",
+	})
+	testQuotaExhausted = RegisterCode("TEST_QUOTA_EXHAUSTED", CodeInfo{
+		Kind:    TooManyRequests,
+		Message: "quota exhausted",
+	})
+)
+
+/*
+TestRegisterCode verifies the code registry.
+*/
+func TestRegisterCode(t *testing.T) {
+	Convey("Given registered codes", t, func() {
+		Convey("When they are looked up", func() {
+			info, ok := LookupCode(testEmailTaken)
+			_, missing := LookupCode("TEST_NEVER_REGISTERED")
+
+			Convey("Then the metadata should be returned with the code filled in", func() {
+				So(ok, ShouldBeTrue)
+				So(info.Code, ShouldEqual, testEmailTaken)
+				So(info.Kind, ShouldEqual, Conflict)
+				So(info.Docs, ShouldEqual, "https://docs.example.com/errors#TEST_EMAIL_TAKEN")
+				So(missing, ShouldBeFalse)
+			})
+		})
+
+		Convey("When every code is listed", func() {
+			codes := Codes()
+
+			Convey("Then they should be sorted by name", func() {
+				names := make([]Code, 0, len(codes))
+
+				for _, info := range codes {
+					names = append(names, info.Code)
+				}
+
+				So(names, ShouldContain, testEmailTaken)
+				So(names, ShouldContain, testQuotaExhausted)
+
+				for index := 1; index < len(names); index++ {
+					So(names[index-1] < names[index], ShouldBeTrue)
+				}
+			})
+		})
+
+		Convey("When a code is registered twice, empty, or without a Kind", func() {
+			Convey("Then RegisterCode should panic", func() {
+				So(func() { RegisterCode(" TEST_EMAIL_TAKEN ", CodeInfo{Kind: Conflict}) }, ShouldPanic)
+				So(func() { RegisterCode("  ", CodeInfo{Kind: Conflict}) }, ShouldPanic)
+				So(func() { RegisterCode("TEST_NO_KIND", CodeInfo{}) }, ShouldPanic)
+			})
+		})
+	})
+}
+
+/*
+TestErrCode verifies coded errors and how the code travels with them.
+*/
+func TestErrCode(t *testing.T) {
+	Convey("Given an error built from a registered code", t, func() {
+		cause := errors.New("duplicate key value")
+		err := ErrCode(testEmailTaken, cause).Operation("user.signup").With("email", "ernie@example.com")
+
+		Convey("When it is inspected", func() {
+			Convey("Then Kind and Message should come from the registry", func() {
+				So(err.Code, ShouldEqual, testEmailTaken)
+				So(IsConflict(err), ShouldBeTrue)
+				So(err.Message, ShouldEqual, "email address is already registered")
+				So(errors.Is(err, cause), ShouldBeTrue)
+			})
+		})
+
+		Convey("When it is wrapped and joined", func() {
+			wrapped := fmt.Errorf("handler: %w", Err(Internal, "signup failed", err))
+			joined := Combine(ErrCode(testQuotaExhausted, nil), wrapped)
+
+			Convey("Then IsCode should find it in any branch", func() {
+				So(IsCode(wrapped, testEmailTaken), ShouldBeTrue)
+				So(IsCode(joined, testEmailTaken), ShouldBeTrue)
+				So(IsCode(joined, testQuotaExhausted), ShouldBeTrue)
+				So(IsCode(wrapped, testQuotaExhausted), ShouldBeFalse)
+				So(IsCode(Err(Internal, "plain", nil), ""), ShouldBeFalse)
+				So(IsCode(nil, testEmailTaken), ShouldBeFalse)
+			})
+
+			Convey("Then CodeOf should return the first code", func() {
+				So(CodeOf(wrapped), ShouldEqual, testEmailTaken)
+				So(CodeOf(joined), ShouldEqual, testQuotaExhausted)
+				So(CodeOf(errors.New("plain")), ShouldEqual, Code(""))
+			})
+		})
+
+		Convey("When it is derived", func() {
+			Convey("Then the copy should keep the code", func() {
+				So(err.Derive("attempt", 2).Code, ShouldEqual, testEmailTaken)
+			})
+		})
+
+		Convey("When it crosses the JSON wire format", func() {
+			encoded, _ := json.Marshal(err)
+			decoded, decodeErr := UnmarshalError(encoded)
+
+			Convey("Then the code should survive", func() {
+				So(decodeErr, ShouldBeNil)
+				So(string(encoded), ShouldContainSubstring, `"code":"TEST_EMAIL_TAKEN"`)
+				So(IsCode(decoded, testEmailTaken), ShouldBeTrue)
+			})
+		})
+
+		Convey("When it is logged through Error and slog", func() {
+			buffer := configureTestLogger(t, log.InfoLevel)
+			Error(err)
+			slog.New(NewSlogHandler(nil)).Info("signup", "err", err)
+
+			Convey("Then both lines should carry the code", func() {
+				So(buffer.String(), ShouldContainSubstring, `"code":"TEST_EMAIL_TAKEN"`)
+				So(buffer.String(), ShouldContainSubstring, `"err.code":"TEST_EMAIL_TAKEN"`)
+			})
+		})
+
+		Convey("When errors with different codes are fingerprinted", func() {
+			Convey("Then they should not group together", func() {
+				So(Fingerprint(ErrCode(testEmailTaken, nil)), ShouldNotEqual,
+					Fingerprint(Err(Conflict, "email address is already registered", nil)))
+			})
+		})
+	})
+
+	Convey("Given a code that was never registered", t, func() {
+		Convey("When ErrCode is called", func() {
+			err := ErrCode("TEST_NEVER_REGISTERED", nil)
+
+			Convey("Then an Unknown error should carry the code as its message", func() {
+				So(err.Kind, ShouldEqual, Unknown)
+				So(err.Message, ShouldEqual, "TEST_NEVER_REGISTERED")
+				So(err.Code, ShouldEqual, Code("TEST_NEVER_REGISTERED"))
+			})
+		})
+	})
+}
+
+/*
+BenchmarkIsCode measures code matching through a wrapped chain.
+*/
+func BenchmarkIsCode(b *testing.B) {
+	err := fmt.Errorf("handler: %w", Err(Internal, "signup failed", ErrCode(testEmailTaken, nil)))
+
+	b.ResetTimer()
+	for range b.N {
+		benchmarkIsCodeSink = IsCode(err, testEmailTaken)
+	}
+}
@@ -0,0 +1,145 @@
+package errnie
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Collector accumulates errors from many goroutines for a later Combine. It
+counts every error by Kind, retains at most limit of them, and counts the rest
+as overflow, so a fan-out that fails a million times does not hold a million
+errors. Counting is lock-free; only retained errors take a short lock.
+
+A zero Collector is ready to use and retains every error.
+
+	collector := errnie.NewCollector(100)
+	var workers sync.WaitGroup
+
+	for _, item := range items {
+		workers.Go(func() { collector.Add(process(item)) })
+	}
+
+	workers.Wait()
+	return collector.Err()
+*/
+type Collector struct {
+	limit    int64
+	total    atomic.Int64
+	reserved atomic.Int64
+	overflow atomic.Int64
+	kinds    sync.Map
+	mutex    sync.Mutex
+	errs     []error
+}
+
+/*
+NewCollector creates a Collector that retains up to limit errors. A limit of
+zero or less retains every error.
+*/
+func NewCollector(limit int) *Collector {
+	return &Collector{limit: int64(max(limit, 0))}
+}
+
+/*
+Add records err. Nil errors are ignored, so Add can take a call's result
+directly. It is safe for concurrent use.
+*/
+func (collector *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	collector.total.Add(1)
+	collector.kindCounter(kindOf(err)).Add(1)
+
+	if collector.limit > 0 && collector.reserved.Add(1) > collector.limit {
+		collector.overflow.Add(1)
+		return
+	}
+
+	collector.mutex.Lock()
+	collector.errs = append(collector.errs, err)
+	collector.mutex.Unlock()
+}
+
+/*
+Len returns the number of errors added, including overflow.
+*/
+func (collector *Collector) Len() int {
+	return int(collector.total.Load())
+}
+
+/*
+Overflow returns the number of errors counted but not retained.
+*/
+func (collector *Collector) Overflow() int {
+	return int(collector.overflow.Load())
+}
+
+/*
+Count returns the number of errors added with kind, including overflow.
+Errors outside the errnie taxonomy count as Unknown.
+*/
+func (collector *Collector) Count(kind Kind) int {
+	counter, ok := collector.kinds.Load(kind)
+	if !ok {
+		return 0
+	}
+
+	return int(counter.(*atomic.Int64).Load())
+}
+
+/*
+Counts returns the number of errors added per Kind, including overflow.
+*/
+func (collector *Collector) Counts() map[Kind]int {
+	counts := make(map[Kind]int)
+
+	collector.kinds.Range(func(kind, counter any) bool {
+		counts[kind.(Kind)] = int(counter.(*atomic.Int64).Load())
+		return true
+	})
+
+	return counts
+}
+
+/*
+Errors returns a copy of the retained errors in the order they were added.
+*/
+func (collector *Collector) Errors() []error {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	return append([]error(nil), collector.errs...)
+}
+
+/*
+Err combines the retained errors with Combine, or returns nil when nothing was
+added. When errors overflowed, a final branch reports how many were dropped.
+Call it after the goroutines feeding the Collector have finished.
+*/
+func (collector *Collector) Err() error {
+	errs := collector.Errors()
+
+	if overflow := collector.Overflow(); overflow > 0 {
+		errs = append(errs, errors.New(strconv.Itoa(overflow)+" more errors dropped by the collector"))
+	}
+
+	return Combine(errs...)
+}
+
+/*
+kindCounter returns the counter for kind, creating it on first use.
+*/
+func (collector *Collector) kindCounter(kind Kind) *atomic.Int64 {
+	if counter, ok := collector.kinds.Load(kind); ok {
+		return counter.(*atomic.Int64)
+	}
+
+	counter, _ := collector.kinds.LoadOrStore(kind, new(atomic.Int64))
+
+	return counter.(*atomic.Int64)
+}
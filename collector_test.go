@@ -0,0 +1,99 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+TestCollector verifies concurrent accumulation, counting, and overflow.
+*/
+func TestCollector(t *testing.T) {
+	Convey("Given a Collector limited to four errors", t, func() {
+		collector := NewCollector(4)
+
+		Convey("When many goroutines add errors of several kinds", func() {
+			var workers sync.WaitGroup
+
+			for index := range 30 {
+				workers.Go(func() {
+					switch index % 3 {
+					case 0:
+						collector.Add(Err(Validation, fmt.Sprintf("item %d invalid", index), nil))
+					case 1:
+						collector.Add(fmt.Errorf("item %d: %w", index, context.DeadlineExceeded))
+					default:
+						collector.Add(nil)
+					}
+				})
+			}
+
+			workers.Wait()
+
+			Convey("Then every error should be counted by Kind", func() {
+				So(collector.Len(), ShouldEqual, 20)
+				So(collector.Count(Validation), ShouldEqual, 10)
+				So(collector.Count(DeadlineExceeded), ShouldEqual, 10)
+				So(collector.Count(NotFound), ShouldEqual, 0)
+				So(collector.Counts(), ShouldResemble, map[Kind]int{Validation: 10, DeadlineExceeded: 10})
+			})
+
+			Convey("Then only the limit should be retained and the rest overflow", func() {
+				So(collector.Errors(), ShouldHaveLength, 4)
+				So(collector.Overflow(), ShouldEqual, 16)
+			})
+
+			Convey("Then Err should join the retained errors and report the overflow", func() {
+				err := collector.Err()
+				branches := err.(interface{ Unwrap() []error }).Unwrap()
+
+				So(branches, ShouldHaveLength, 5)
+				So(branches[4].Error(), ShouldEqual, "16 more errors dropped by the collector")
+			})
+		})
+	})
+
+	Convey("Given a zero Collector", t, func() {
+		var collector Collector
+
+		Convey("When nothing has been added", func() {
+			Convey("Then Err should be nil", func() {
+				So(collector.Err(), ShouldBeNil)
+				So(collector.Counts(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When errors are added", func() {
+			first := Err(IO, "disk full", nil)
+			second := errors.New("plain")
+
+			for range 100 {
+				collector.Add(first)
+			}
+
+			collector.Add(second)
+
+			Convey("Then every error should be retained in order", func() {
+				So(collector.Errors(), ShouldHaveLength, 101)
+				So(collector.Overflow(), ShouldEqual, 0)
+				So(collector.Count(Unknown), ShouldEqual, 1)
+				So(errors.Is(collector.Err(), second), ShouldBeTrue)
+				So(IsIO(collector.Err()), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a single error is added", func() {
+			only := Err(NotFound, "missing", nil)
+			collector.Add(only)
+
+			Convey("Then Err should return it unjoined", func() {
+				So(collector.Err(), ShouldEqual, only)
+			})
+		})
+	})
+}
@@ -6,9 +6,16 @@ via mapstructure tags (for example with Viper). Pass a populated Config to Apply
 after configuration is loaded.
 */
 type Config struct {
-	Level         string `mapstructure:"level"`
-	DisableCaller bool   `mapstructure:"disable_caller"`
-	File          struct {
+	Level               string `mapstructure:"level"`
+	DisableCaller       bool   `mapstructure:"disable_caller"`
+	StackTraces         bool   `mapstructure:"stack_traces"`
+	FingerprintLocation bool   `mapstructure:"fingerprint_location"`
+	Redaction           struct {
+		Keys     []string `mapstructure:"keys"`
+		Patterns []string `mapstructure:"patterns"`
+		Mask     string   `mapstructure:"mask"`
+	} `mapstructure:"redaction"`
+	File struct {
 		Active bool   `mapstructure:"active"`
 		Path   string `mapstructure:"path"`
 	} `mapstructure:"file"`
@@ -1,5 +1,7 @@
 package errnie
 
+import "slices"
+
 /*
 Result holds the outcome of a function executed through Does. The value and
 error are kept together so callers can inspect or handle failures without
@@ -77,3 +79,114 @@ fn succeeded.
 func (result Result[T]) Err() error {
 	return result.err
 }
+
+/*
+ValueOr returns the value on success and fallback on failure.
+*/
+func (result Result[T]) ValueOr(fallback T) T {
+	if result.err != nil {
+		return fallback
+	}
+
+	return result.value
+}
+
+/*
+OrElse calls fn with the error on failure and returns its outcome instead,
+for example to read from a fallback source. Successes are returned unchanged.
+*/
+func (result Result[T]) OrElse(fn func(error) (T, error)) Result[T] {
+	if result.err == nil {
+		return result
+	}
+
+	value, err := fn(result.err)
+
+	return Result[T]{value: value, err: err}
+}
+
+/*
+Recover turns failures of the given kinds into successes with the value fn
+returns for the error. Without kinds, every failure is recovered. Other
+failures and successes are returned unchanged.
+
+	user := errnie.Does(load).Recover(func(error) User { return Guest }, errnie.NotFound)
+*/
+func (result Result[T]) Recover(fn func(error) T, kinds ...Kind) Result[T] {
+	if result.err == nil {
+		return result
+	}
+
+	if len(kinds) > 0 && !slices.Contains(kinds, kindOf(result.err)) {
+		return result
+	}
+
+	return Result[T]{value: fn(result.err)}
+}
+
+/*
+Must returns the value, panicking with the error on failure. Use it where a
+failure is a programming error, such as parsing a constant at init.
+*/
+func (result Result[T]) Must() T {
+	if result.err != nil {
+		panic(result.err)
+	}
+
+	return result.value
+}
+
+/*
+Tap calls fn with the value on success, the counterpart of Or. The same Result
+is returned so Tap and Or can be chained.
+*/
+func (result Result[T]) Tap(fn func(T)) Result[T] {
+	if result.err == nil {
+		fn(result.value)
+	}
+
+	return result
+}
+
+/*
+Map transforms the value of a successful Result with fn. A failure is carried
+over with the zero value of U and fn is not called. Map is a function rather
+than a method because methods cannot add type parameters.
+
+	name := errnie.Map(errnie.Does(load), User.DisplayName)
+*/
+func Map[T, U any](result Result[T], fn func(T) U) Result[U] {
+	if result.err != nil {
+		return Result[U]{err: result.err}
+	}
+
+	return Result[U]{value: fn(result.value)}
+}
+
+/*
+FlatMap chains a step that itself returns a Result. A failure is carried over
+and fn is not called.
+*/
+func FlatMap[T, U any](result Result[T], fn func(T) Result[U]) Result[U] {
+	if result.err != nil {
+		return Result[U]{err: result.err}
+	}
+
+	return fn(result.value)
+}
+
+/*
+AndThen chains a step with the usual (value, error) signature, like Does but
+fed with the previous value. A failure is carried over and fn is not called.
+
+	order := errnie.AndThen(errnie.Does(loadCart), checkout)
+*/
+func AndThen[T, U any](result Result[T], fn func(T) (U, error)) Result[U] {
+	if result.err != nil {
+		return Result[U]{err: result.err}
+	}
+
+	value, err := fn(result.value)
+
+	return Result[U]{value: value, err: err}
+}
@@ -0,0 +1,99 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+/*
+DoesCtx runs fn with ctx and returns as soon as fn finishes or ctx is done,
+whichever comes first. When ctx ends first the Result fails with a Canceled or
+DeadlineExceeded ErrnieError carrying the field deadline_source=context and
+context.Cause(ctx) as its cause. A panic in fn fails the Result with an
+Internal error (see Recover).
+
+A ctx that can never be done, such as context.Background, runs fn on the
+calling goroutine. Otherwise fn runs on its own goroutine. If fn honours ctx,
+that goroutine ends right after ctx does. If fn ignores ctx, the goroutine
+keeps running until fn returns, and its value is discarded.
+*/
+func DoesCtx[T any](ctx context.Context, fn func(context.Context) (T, error)) Result[T] {
+	return doesBounded(ctx, ctx, 0, fn)
+}
+
+/*
+DoesWithin runs fn like DoesCtx under a time budget of d. When the budget runs
+out first the Result fails with a Timeout ErrnieError carrying the fields
+deadline_source=budget and budget=d. It has no cause, so it stays retryable.
+If ctx itself ends first, the error is the same as from DoesCtx. An error fn
+returns on its own, such as an upstream Timeout, is passed through unchanged
+and has no deadline_source field, so callers can tell the three cases apart:
+
+	result := errnie.DoesWithin(ctx, 200*time.Millisecond, client.Fetch)
+*/
+func DoesWithin[T any](ctx context.Context, d time.Duration, fn func(context.Context) (T, error)) Result[T] {
+	bounded, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	return doesBounded(ctx, bounded, d, fn)
+}
+
+/*
+doesBounded runs fn under bounded, a context derived from parent, and
+classifies an early end by whether parent or the budget ran out.
+*/
+func doesBounded[T any](
+	parent, bounded context.Context, budget time.Duration, fn func(context.Context) (T, error),
+) Result[T] {
+	if bounded.Done() == nil {
+		var value T
+
+		err := runRecovered(bounded, func(ctx context.Context) (err error) {
+			value, err = fn(ctx)
+			return err
+		})
+
+		return Result[T]{value: value, err: err}
+	}
+
+	if bounded.Err() != nil {
+		return Result[T]{err: deadlineError(parent, budget)}
+	}
+
+	done := make(chan Result[T], 1)
+
+	go func() {
+		var value T
+
+		err := runRecovered(bounded, func(ctx context.Context) (err error) {
+			value, err = fn(ctx)
+			return err
+		})
+
+		done <- Result[T]{value: value, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil && bounded.Err() != nil && errors.Is(result.err, bounded.Err()) {
+			result.err = deadlineError(parent, budget)
+		}
+
+		return result
+	case <-bounded.Done():
+		return Result[T]{err: deadlineError(parent, budget)}
+	}
+}
+
+/*
+deadlineError reports that fn was cut short, by the budget when parent is
+still live and by parent otherwise.
+*/
+func deadlineError(parent context.Context, budget time.Duration) *ErrnieError {
+	if parent.Err() == nil {
+		return Err(Timeout, "time budget exceeded", nil).With("deadline_source", "budget", "budget", budget)
+	}
+
+	return Err(kindOf(parent.Err()), "context ended before fn returned", context.Cause(parent)).With("deadline_source", "context")
+}
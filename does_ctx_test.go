@@ -0,0 +1,221 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+benchmarkDoesCtxFn is a named context-aware function for the DoesCtx
+benchmarks.
+*/
+func benchmarkDoesCtxFn(context.Context) (int, error) {
+	return 42, nil
+}
+
+/*
+TestDoesCtx verifies context-aware execution and cancellation reporting.
+*/
+func TestDoesCtx(t *testing.T) {
+	Convey("Given a context that can never be done", t, func() {
+		Convey("When DoesCtx runs a function", func() {
+			result := DoesCtx(context.Background(), func(context.Context) (string, error) {
+				return "ernie", nil
+			})
+
+			Convey("Then the Result should hold its value", func() {
+				So(result.Value(), ShouldEqual, "ernie")
+				So(result.Err(), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a cancellable context", t, func() {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		defer cancel(nil)
+
+		Convey("When the function returns first", func() {
+			result := DoesCtx(ctx, func(context.Context) (int, error) {
+				return 0, Err(NotFound, "missing", nil)
+			})
+
+			Convey("Then its error should pass through unchanged", func() {
+				So(result.Err().Error(), ShouldEqual, "missing")
+			})
+		})
+
+		Convey("When the context is cancelled while the function ignores it", func() {
+			release := make(chan struct{})
+			defer close(release)
+
+			cause := errors.New("client went away")
+
+			time.AfterFunc(time.Millisecond, func() { cancel(cause) })
+
+			result := DoesCtx(ctx, func(context.Context) (int, error) {
+				<-release
+				return 1, nil
+			})
+
+			Convey("Then DoesCtx should return a Canceled error with the cause", func() {
+				target, _ := AsErrnie(result.Err())
+				So(IsCanceled(result.Err()), ShouldBeTrue)
+				So(errors.Is(result.Err(), cause), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"deadline_source", "context"})
+			})
+		})
+
+		Convey("When the context is already cancelled", func() {
+			cancel(nil)
+			called := false
+
+			result := DoesCtx(ctx, func(context.Context) (int, error) {
+				called = true
+				return 1, nil
+			})
+
+			Convey("Then the function should not be called", func() {
+				So(called, ShouldBeFalse)
+				So(IsCanceled(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a function that panics", t, func() {
+		Convey("When it runs through DoesCtx", func() {
+			background := DoesCtx(context.Background(), func(context.Context) (int, error) {
+				panic("inline")
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			goroutine := DoesCtx(ctx, func(context.Context) (int, error) {
+				panic("detached")
+			})
+
+			Convey("Then both paths should fail with Internal", func() {
+				So(IsInternal(background.Err()), ShouldBeTrue)
+				So(IsInternal(goroutine.Err()), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+TestDoesWithin verifies time budgets and how their errors are told apart.
+*/
+func TestDoesWithin(t *testing.T) {
+	Convey("Given a function that honours its context", t, func() {
+		slow := func(ctx context.Context) (int, error) {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(time.Second):
+				return 1, nil
+			}
+		}
+
+		Convey("When the budget runs out", func() {
+			result := DoesWithin(context.Background(), 5*time.Millisecond, slow)
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then the error should be a retryable budget Timeout", func() {
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+				So(IsContext(result.Err()), ShouldBeFalse)
+				So(IsRetryable(result.Err()), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"deadline_source", "budget", "budget", 5 * time.Millisecond})
+			})
+		})
+
+		Convey("When the caller's deadline is shorter than the budget", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+			defer cancel()
+
+			result := DoesWithin(ctx, time.Minute, slow)
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then the error should blame the context", func() {
+				So(IsDeadlineExceeded(result.Err()), ShouldBeTrue)
+				So(errors.Is(result.Err(), context.DeadlineExceeded), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"deadline_source", "context"})
+			})
+		})
+
+		Convey("When the budget is zero", func() {
+			result := DoesWithin(context.Background(), 0, slow)
+
+			Convey("Then the budget should already be spent", func() {
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a function that ignores its context", t, func() {
+		finished := make(chan struct{})
+
+		Convey("When the budget runs out", func() {
+			start := time.Now()
+			result := DoesWithin(context.Background(), 5*time.Millisecond, func(context.Context) (int, error) {
+				defer close(finished)
+				time.Sleep(200 * time.Millisecond)
+				return 1, nil
+			})
+
+			Convey("Then DoesWithin should return on time while the function finishes later", func() {
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+				So(time.Since(start), ShouldBeLessThan, 150*time.Millisecond)
+				<-finished
+			})
+		})
+	})
+
+	Convey("Given an upstream that reports its own timeout", t, func() {
+		Convey("When it fails within the budget", func() {
+			result := DoesWithin(context.Background(), time.Second, func(context.Context) (int, error) {
+				return 0, Err(Timeout, "upstream timed out", nil)
+			})
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then the error should carry no deadline_source", func() {
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+				So(target.Fields(), ShouldBeNil)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkDoesCtx measures DoesCtx and DoesWithin on the success path.
+*/
+func BenchmarkDoesCtx(b *testing.B) {
+	b.Run("background", func(b *testing.B) {
+		ctx := context.Background()
+
+		for range b.N {
+			benchmarkResultInt = DoesCtx(ctx, benchmarkDoesCtxFn)
+		}
+	})
+
+	b.Run("cancellable", func(b *testing.B) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		b.ResetTimer()
+		for range b.N {
+			benchmarkResultInt = DoesCtx(ctx, benchmarkDoesCtxFn)
+		}
+	})
+
+	b.Run("within", func(b *testing.B) {
+		ctx := context.Background()
+
+		for range b.N {
+			benchmarkResultInt = DoesWithin(ctx, time.Second, benchmarkDoesCtxFn)
+		}
+	})
+}
@@ -308,6 +308,95 @@ func TestResultErr(t *testing.T) {
 	})
 }
 
+/*
+TestResultCombinators verifies Map, FlatMap, AndThen, and the Result methods
+that replace if-err blocks.
+*/
+func TestResultCombinators(t *testing.T) {
+	double := func(value int) int { return value * 2 }
+	missing := Err(NotFound, "user missing", nil)
+
+	Convey("Given a successful Result", t, func() {
+		result := Does(func() (int, error) { return 21, nil })
+
+		Convey("When it is mapped and chained", func() {
+			mapped := Map(result, double)
+			flat := FlatMap(mapped, func(value int) Result[string] {
+				return Does(func() (string, error) { return "answer", nil })
+			})
+			then := AndThen(result, func(value int) (bool, error) { return value > 20, nil })
+
+			Convey("Then each step should see the previous value", func() {
+				So(mapped.Value(), ShouldEqual, 42)
+				So(flat.Value(), ShouldEqual, "answer")
+				So(then.Value(), ShouldBeTrue)
+				So(then.Err(), ShouldBeNil)
+			})
+		})
+
+		Convey("When the failure helpers are used", func() {
+			var tapped int
+
+			Convey("Then the value should pass through untouched", func() {
+				So(result.ValueOr(7), ShouldEqual, 21)
+				So(result.Must(), ShouldEqual, 21)
+				So(result.OrElse(func(error) (int, error) { return 0, nil }).Value(), ShouldEqual, 21)
+				So(result.Recover(func(error) int { return 0 }).Value(), ShouldEqual, 21)
+				So(result.Tap(func(value int) { tapped = value }).Value(), ShouldEqual, 21)
+				So(tapped, ShouldEqual, 21)
+			})
+		})
+	})
+
+	Convey("Given a failed Result", t, func() {
+		result := Does(func() (int, error) { return 0, missing })
+
+		Convey("When it is mapped and chained", func() {
+			called := false
+			mapped := Map(result, func(value int) int { called = true; return value })
+			flat := FlatMap(result, func(int) Result[string] { called = true; return Result[string]{} })
+			then := AndThen(result, func(int) (string, error) { called = true; return "", nil })
+
+			Convey("Then the error should be carried over without calling the steps", func() {
+				So(called, ShouldBeFalse)
+				So(mapped.Err(), ShouldEqual, missing)
+				So(flat.Err(), ShouldEqual, missing)
+				So(then.Err(), ShouldEqual, missing)
+			})
+		})
+
+		Convey("When ValueOr, OrElse, and Tap are used", func() {
+			fallback := result.OrElse(func(err error) (int, error) {
+				return 5, nil
+			})
+
+			Convey("Then the fallback should replace the failure", func() {
+				So(result.ValueOr(7), ShouldEqual, 7)
+				So(fallback.Value(), ShouldEqual, 5)
+				So(fallback.Err(), ShouldBeNil)
+				So(result.Tap(func(int) { t.Error("Tap called on failure") }).Err(), ShouldEqual, missing)
+			})
+		})
+
+		Convey("When Recover selects kinds", func() {
+			guest := func(error) int { return -1 }
+
+			Convey("Then only matching kinds should become values", func() {
+				So(result.Recover(guest, NotFound).Value(), ShouldEqual, -1)
+				So(result.Recover(guest, NotFound).Err(), ShouldBeNil)
+				So(result.Recover(guest, Conflict, Internal).Err(), ShouldEqual, missing)
+				So(result.Recover(guest).Value(), ShouldEqual, -1)
+			})
+		})
+
+		Convey("When Must is called", func() {
+			Convey("Then it should panic with the error", func() {
+				So(func() { result.Must() }, ShouldPanicWith, missing)
+			})
+		})
+	})
+}
+
 /*
 doesCustomError is a custom error type used to assert error identity in Or
 and Err tests.
@@ -382,6 +471,30 @@ func benchmarkDoesErrCustomFn() (int, error) {
 
 func benchmarkNoOpHandler(error) {}
 
+func benchmarkDoubleFn(value int) int {
+	return value * 2
+}
+
+func benchmarkFormatFn(value int) (string, error) {
+	if value < 0 {
+		return "", benchmarkSomeErr
+	}
+
+	return "positive", nil
+}
+
+func benchmarkFallbackFn(error) (int, error) {
+	return 7, nil
+}
+
+func benchmarkRecoverFn(error) int {
+	return -1
+}
+
+func benchmarkTapFn(value int) {
+	benchmarkIntSink = value
+}
+
 /*
 BenchmarkDoes measures Does for successful, failed, and alternate value types.
 Uses named functions and typed sinks so results reflect Does rather than closure
@@ -502,3 +615,61 @@ func BenchmarkResultErr(b *testing.B) {
 		}
 	})
 }
+
+/*
+BenchmarkResultCombinators measures Map, AndThen, and the fallback methods on
+success and failure results.
+*/
+func BenchmarkResultCombinators(b *testing.B) {
+	success := Does(benchmarkDoesSuccessFn)
+	failure := Does(benchmarkDoesErrorFn)
+	typedFailure := Result[int]{err: Err(NotFound, "benchmark miss", nil)}
+
+	b.Run("map success", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultInt = Map(success, benchmarkDoubleFn)
+		}
+	})
+
+	b.Run("map error", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultInt = Map(failure, benchmarkDoubleFn)
+		}
+	})
+
+	b.Run("and then success", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultString = AndThen(success, benchmarkFormatFn)
+		}
+	})
+
+	b.Run("value or error", func(b *testing.B) {
+		for range b.N {
+			benchmarkIntSink = failure.ValueOr(7)
+		}
+	})
+
+	b.Run("or else error", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultInt = failure.OrElse(benchmarkFallbackFn)
+		}
+	})
+
+	b.Run("recover kind", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultInt = failure.Recover(benchmarkRecoverFn, NotFound, Unknown)
+		}
+	})
+
+	b.Run("recover errnie kind", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultInt = typedFailure.Recover(benchmarkRecoverFn, Timeout, NotFound)
+		}
+	})
+
+	b.Run("tap success", func(b *testing.B) {
+		for range b.N {
+			benchmarkResultInt = success.Tap(benchmarkTapFn)
+		}
+	})
+}
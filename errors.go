@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
 )
 
 /*
@@ -44,19 +47,39 @@ var (
 ErrnieError is the canonical typed error for errnie-aware projects. Use Kind
 for semantic classification, Message for human-readable detail, Cause for
 wrapping, and With for structured metadata. ErrnieError supports errors.Is
-and errors.As through Unwrap; it does not capture stack traces.
+and errors.As through Unwrap. Stack traces are off by default; enable them with
+Config.StackTraces to record program counters in Err and Guard. MessageKey and
+MessageArgs are set by ErrKey for messages resolved through a Catalog, and Code
+by ErrCode for errors registered with RegisterCode.
 
 Construct and enrich an ErrnieError (E, Operation, With) before sharing it
-across goroutines. Mutation after concurrent use is not safe.
+across goroutines. Mutation after concurrent use is not safe. To enrich an error
+that is already shared, such as a package-level template, use Clone or the
+Derive methods, which return independent copies and never touch the receiver.
 */
 type ErrnieError struct {
-	Kind      Kind
-	Op        string
-	Message   string
-	Cause     error
-	Timestamp int64
-	fields    []any
-	rendered  string
+	Kind        Kind
+	Op          string
+	Message     string
+	MessageKey  string
+	MessageArgs []any
+	Code        Code
+	Cause       error
+	Timestamp   int64
+	fields      []any
+	rendered    atomic.Pointer[renderedText]
+	stack       *stackTrace
+}
+
+/*
+renderedText is the cached result of Error, stamped with the redaction policy
+and catalog it was rendered under so a later SetRedactionPolicy or SetCatalog
+invalidates it.
+*/
+type renderedText struct {
+	text    string
+	policy  *RedactionPolicy
+	catalog *catalogState
 }
 
 /*
@@ -65,11 +88,7 @@ cause. Cause is preserved for errors.Is and errors.As, including
 context.Canceled and context.DeadlineExceeded when passed as cause.
 */
 func Err(kind Kind, message string, cause error) *ErrnieError {
-	return &ErrnieError{
-		Kind:    kind,
-		Message: message,
-		Cause:   cause,
-	}
+	return newErr(kind, message, cause, 2)
 }
 
 /*
@@ -81,7 +100,26 @@ func Guard(kind Kind, message string, cause error) error {
 		return nil
 	}
 
-	return Err(kind, message, cause)
+	return newErr(kind, message, cause, 2)
+}
+
+/*
+newErr builds an ErrnieError for Err and Guard. When stack traces are enabled
+it records the call stack, dropping skip frames counted from newErr itself,
+unless the cause chain already carries a trace.
+*/
+func newErr(kind Kind, message string, cause error, skip int) *ErrnieError {
+	err := &ErrnieError{
+		Kind:    kind,
+		Message: message,
+		Cause:   cause,
+	}
+
+	if stackTraces.Load() && stackOf(cause) == nil {
+		err.stack = captureStack(skip)
+	}
+
+	return err
 }
 
 func (err *ErrnieError) WithTimestamp(timestamp int64) *ErrnieError {
@@ -103,7 +141,7 @@ func (err *ErrnieError) Operation(name string) *ErrnieError {
 	}
 
 	err.Op = name
-	err.rendered = ""
+	err.rendered.Store(nil)
 
 	return err
 }
@@ -141,9 +179,89 @@ func (err *ErrnieError) With(keysAndValues ...any) *ErrnieError {
 		}
 	}
 
+	err.rendered.Store(nil)
+
 	return err
 }
 
+/*
+Clone returns a copy of the error that shares no mutable state with the
+receiver. The copy keeps the Kind, Op, Message, Cause, Timestamp, fields, and
+stack trace, so enriching it with Operation, With, or WithTimestamp leaves the
+original untouched.
+*/
+func (err *ErrnieError) Clone() *ErrnieError {
+	if err == nil {
+		return nil
+	}
+
+	return err.derive(0)
+}
+
+/*
+Derive returns a copy of the error with keysAndValues attached as by With. The
+receiver is only read, so a shared template can be derived from concurrently:
+
+	var errUserMissing = errnie.Err(errnie.NotFound, "user missing", nil)
+
+	return errUserMissing.Derive("user_id", id)
+*/
+func (err *ErrnieError) Derive(keysAndValues ...any) *ErrnieError {
+	if err == nil {
+		return nil
+	}
+
+	return err.derive(len(keysAndValues)).With(keysAndValues...)
+}
+
+/*
+DeriveOperation returns a copy of the error with Op set to name.
+*/
+func (err *ErrnieError) DeriveOperation(name string) *ErrnieError {
+	if err == nil {
+		return nil
+	}
+
+	return err.derive(0).Operation(name)
+}
+
+/*
+DeriveTimestamp returns a copy of the error with Timestamp set.
+*/
+func (err *ErrnieError) DeriveTimestamp(timestamp int64) *ErrnieError {
+	if err == nil {
+		return nil
+	}
+
+	return err.derive(0).WithTimestamp(timestamp)
+}
+
+/*
+derive copies the error into a new value whose field slice has its own backing
+array with room for extra more entries, so appends on the copy never write into
+storage the receiver can see.
+*/
+func (err *ErrnieError) derive(extra int) *ErrnieError {
+	derived := &ErrnieError{
+		Kind:        err.Kind,
+		Op:          err.Op,
+		Message:     err.Message,
+		MessageKey:  err.MessageKey,
+		MessageArgs: err.MessageArgs,
+		Code:        err.Code,
+		Cause:       err.Cause,
+		Timestamp:   err.Timestamp,
+		stack:       err.stack,
+	}
+
+	if len(err.fields) > 0 || extra > 0 {
+		derived.fields = make([]any, len(err.fields), len(err.fields)+extra)
+		copy(derived.fields, err.fields)
+	}
+
+	return derived
+}
+
 /*
 Fields returns the metadata slice attached via With as alternating key/value
 pairs, or nil when none was added. The returned slice must be treated as
@@ -158,18 +276,25 @@ func (err *ErrnieError) Fields() []any {
 }
 
 /*
-Error implements the error interface. When Op is set it prefixes the message.
+Error implements the error interface. When Op is set it prefixes the message,
+and fields follow as key=value pairs after redaction (see RedactionPolicy).
+The rendered text is cached per redaction policy, so a policy applied later
+still masks it, and the cache is safe to read and fill from several
+goroutines.
 */
 func (err *ErrnieError) Error() string {
 	if err == nil {
 		return ""
 	}
 
-	if err.rendered != "" {
-		return err.rendered
+	policy := redactionPolicySnapshot()
+	catalog := activeCatalog.Load()
+
+	if cached := err.rendered.Load(); cached != nil && cached.policy == policy && cached.catalog == catalog {
+		return cached.text
 	}
 
-	message := err.Message
+	message := err.message()
 
 	if message == "" && err.Cause != nil {
 		message = err.Cause.Error()
@@ -179,19 +304,66 @@ func (err *ErrnieError) Error() string {
 		message = err.Kind.Error()
 	}
 
+	rendered := message
+
 	if err.Op != "" {
-		err.rendered = err.Op + ": " + message
-	} else {
-		err.rendered = message
+		rendered = err.Op + ": " + message
 	}
 
 	fields := err.Fields()
 
 	for index := 0; index+1 < len(fields); index += 2 {
-		err.rendered += fmt.Sprintf(" %s=%v", fields[index], fields[index+1])
+		key, _ := fields[index].(string)
+		value, _ := policy.redact(key, fields[index+1])
+		rendered += fmt.Sprintf(" %s=%v", key, value)
 	}
 
-	return err.rendered
+	err.rendered.Store(&renderedText{text: rendered, policy: policy, catalog: catalog})
+
+	return rendered
+}
+
+/*
+message returns Message, or for an error built with ErrKey the MessageKey
+resolved in the fallback locale of the active catalog, so a catalog installed
+or swapped after construction is still used.
+*/
+func (err *ErrnieError) message() string {
+	if err.MessageKey != "" {
+		if message, ok := resolveMessage("", err.MessageKey, err.MessageArgs); ok {
+			return message
+		}
+	}
+
+	return err.Message
+}
+
+/*
+StackTrace returns the symbolized frames recorded for this error, or for the
+nearest cause that carries a trace. It returns nil when stack traces were
+disabled at construction.
+*/
+func (err *ErrnieError) StackTrace() []runtime.Frame {
+	return stackOf(err).Frames()
+}
+
+/*
+Format implements fmt.Formatter. %v and %s print Error() and %q quotes it. %+v
+writes the whole error tree, one node per line with its Kind, Op, message,
+fields, and location, followed by the symbolized stack trace when one was
+recorded.
+*/
+func (err *ErrnieError) Format(state fmt.State, verb rune) {
+	formatError(state, verb, err)
+
+	if verb != 'v' || !state.Flag('+') {
+		return
+	}
+
+	if trace := stackOf(err); trace != nil {
+		io.WriteString(state, "\n"+treeIndent+"stack:")
+		io.WriteString(state, strings.ReplaceAll("\n"+trace.String(), "\n", "\n"+treeIndent+treeIndent))
+	}
 }
 
 /*
@@ -227,8 +399,9 @@ func (joined joinedPair) Unwrap() []error {
 
 /*
 Combine joins non-nil errors. Returns nil when every error is nil. The common
-two-error cleanup path uses a specialized joinPair; three or more fall back to
-errors.Join.
+two-error cleanup path uses a specialized joinPair; three or more use
+joinedErrors, which behaves like errors.Join. Both print the whole tree with
+%+v.
 */
 func Combine(errs ...error) error {
 	var first, second error
@@ -265,7 +438,7 @@ func Combine(errs ...error) error {
 	case 2:
 		return joinPair(first, second)
 	default:
-		return errors.Join(extra...)
+		return &joinedErrors{unwrapped: extra}
 	}
 }
 
@@ -301,6 +474,30 @@ func IsKind(err error, kind Kind) bool {
 	return false
 }
 
+/*
+kindOf returns the Kind of the first ErrnieError in err's chain. Bare Kind
+sentinels such as context.Canceled map to themselves, wrapped context errors to
+their context Kinds, and anything else to Unknown.
+*/
+func kindOf(err error) Kind {
+	if target, ok := asErrnieInChain(err); ok && target.Kind != nil {
+		return target.Kind
+	}
+
+	if info, ok := LookupKind(err); ok {
+		return info.Kind
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	default:
+		return Unknown
+	}
+}
+
 /*
 asErrnieInChain walks an error chain without errors.As reflection.
 */
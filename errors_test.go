@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -200,6 +201,16 @@ func TestErrnieErrorError(t *testing.T) {
 				So(third, ShouldEqual, "user.create: invalid email")
 			})
 		})
+
+		Convey("When With adds a field after Error was cached", func() {
+			first := err.Error()
+			err.With("field", "email")
+
+			Convey("Then the cached text should include the new field", func() {
+				So(first, ShouldEqual, "invalid email")
+				So(err.Error(), ShouldEqual, "invalid email field=email")
+			})
+		})
 	})
 
 	Convey("Given a nil ErrnieError", t, func() {
@@ -215,6 +226,134 @@ func TestErrnieErrorError(t *testing.T) {
 	})
 }
 
+/*
+TestErrnieErrorClone verifies that a clone shares no mutable state with its
+source.
+*/
+func TestErrnieErrorClone(t *testing.T) {
+	Convey("Given an enriched ErrnieError", t, func() {
+		cause := errors.New("disk full")
+		original := Err(IO, "write failed", cause).Operation("file.save").WithTimestamp(42).With("path", "/tmp/a")
+
+		Convey("When it is cloned and the clone is mutated", func() {
+			clone := original.Clone()
+			clone.With("path", "/tmp/b", "attempt", 2).Operation("file.retry")
+
+			Convey("Then the clone should carry the original values plus its own changes", func() {
+				So(clone, ShouldNotPointTo, original)
+				So(clone.Kind, ShouldEqual, IO)
+				So(clone.Message, ShouldEqual, "write failed")
+				So(clone.Timestamp, ShouldEqual, 42)
+				So(errors.Is(clone, cause), ShouldBeTrue)
+				So(clone.Error(), ShouldEqual, "file.retry: write failed path=/tmp/b attempt=2")
+			})
+
+			Convey("Then the original should be unchanged", func() {
+				So(original.Fields(), ShouldResemble, []any{"path", "/tmp/a"})
+				So(original.Error(), ShouldEqual, "file.save: write failed path=/tmp/a")
+			})
+		})
+	})
+
+	Convey("Given a nil ErrnieError", t, func() {
+		var err *ErrnieError
+
+		Convey("When Clone and the Derive methods are called", func() {
+			Convey("Then they should return nil", func() {
+				So(err.Clone(), ShouldBeNil)
+				So(err.Derive("key", "value"), ShouldBeNil)
+				So(err.DeriveOperation("op"), ShouldBeNil)
+				So(err.DeriveTimestamp(1), ShouldBeNil)
+			})
+		})
+	})
+}
+
+/*
+TestErrnieErrorDerive verifies the copy-returning enrichment methods.
+*/
+func TestErrnieErrorDerive(t *testing.T) {
+	Convey("Given a template ErrnieError whose fields have spare capacity", t, func() {
+		template := Err(NotFound, "user missing", nil)
+		template.fields = make([]any, 0, 8)
+		template.With("tenant", "acme")
+
+		Convey("When two copies are derived with different fields", func() {
+			first := template.Derive("user_id", 1)
+			second := template.Derive("user_id", 2)
+
+			Convey("Then each copy should keep its own fields", func() {
+				So(first.Fields(), ShouldResemble, []any{"tenant", "acme", "user_id", 1})
+				So(second.Fields(), ShouldResemble, []any{"tenant", "acme", "user_id", 2})
+				So(template.Fields(), ShouldResemble, []any{"tenant", "acme"})
+			})
+		})
+
+		Convey("When a derived copy replaces an inherited field", func() {
+			derived := template.Derive("tenant", "globex")
+
+			Convey("Then the template value should be untouched", func() {
+				So(derived.Fields(), ShouldResemble, []any{"tenant", "globex"})
+				So(template.Fields(), ShouldResemble, []any{"tenant", "acme"})
+			})
+		})
+
+		Convey("When DeriveOperation and DeriveTimestamp are chained", func() {
+			derived := template.DeriveOperation("user.load").DeriveTimestamp(7)
+
+			Convey("Then only the copy should change", func() {
+				So(derived.Op, ShouldEqual, "user.load")
+				So(derived.Timestamp, ShouldEqual, 7)
+				So(derived.Error(), ShouldEqual, "user.load: user missing tenant=acme")
+				So(template.Op, ShouldEqual, "")
+				So(template.Timestamp, ShouldEqual, 0)
+				So(template.Error(), ShouldEqual, "user missing tenant=acme")
+			})
+		})
+	})
+}
+
+/*
+TestErrnieErrorDeriveConcurrent verifies that a shared template can be
+rendered and derived from many goroutines at once. Run with -race.
+*/
+func TestErrnieErrorDeriveConcurrent(t *testing.T) {
+	Convey("Given a package-level style template shared across goroutines", t, func() {
+		template := Err(Unauthorized, "token rejected", nil).With("realm", "api")
+
+		Convey("When goroutines render it and derive request-scoped copies", func() {
+			const workers = 64
+
+			var group sync.WaitGroup
+			rendered := make([]string, workers)
+			derived := make([]string, workers)
+
+			for worker := range workers {
+				group.Go(func() {
+					rendered[worker] = template.Error()
+					derived[worker] = template.
+						Derive("request_id", worker).
+						DeriveOperation("auth.check").
+						Error()
+				})
+			}
+
+			group.Wait()
+
+			Convey("Then every goroutine should see consistent text", func() {
+				for worker := range workers {
+					So(rendered[worker], ShouldEqual, "token rejected realm=api")
+					So(derived[worker], ShouldEqual, fmt.Sprintf("auth.check: token rejected realm=api request_id=%d", worker))
+				}
+			})
+
+			Convey("Then the template should keep only its own fields", func() {
+				So(template.Fields(), ShouldResemble, []any{"realm", "api"})
+			})
+		})
+	})
+}
+
 /*
 TestErrnieErrorUnwrap verifies wrapping support for errors.Is and errors.As.
 */
@@ -607,6 +746,20 @@ func BenchmarkErrnieErrorWith(b *testing.B) {
 	})
 }
 
+/*
+BenchmarkErrnieErrorDerive measures copy-returning enrichment of a shared
+template.
+*/
+func BenchmarkErrnieErrorDerive(b *testing.B) {
+	template := Err(NotFound, "user missing", nil).With("tenant", "acme")
+
+	b.Run("one field", func(b *testing.B) {
+		for range b.N {
+			benchmarkErrnieSink = template.Derive("user_id", 42)
+		}
+	})
+}
+
 /*
 BenchmarkErrnieErrorError measures Error string formatting.
 */
@@ -0,0 +1,210 @@
+package errnie
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+FNV-1a 64-bit parameters. The hash is computed inline so fingerprinting an
+error on the logging hot path does not allocate a hash.Hash.
+*/
+const (
+	fingerprintOffset = 14695981039346656037
+	fingerprintPrime  = 1099511628211
+)
+
+/*
+fingerprintLocation is the package-level switch for mixing the constructing
+function into fingerprints. Apply sets it from Config.FingerprintLocation; it
+only has an effect on errors that recorded a stack trace.
+*/
+var fingerprintLocation atomic.Bool
+
+/*
+Fingerprint returns a stable 16 character hex identifier that groups errors of
+the same shape, the way an error tracker groups events. It hashes the Kind
+name, Code, Op, and message of every ErrnieError in the chain, the text of other
+wrapped errors, and the branches of joined errors. Variable parts of messages
+are removed first: words containing digits and quoted strings do not affect
+the result, so "user 42 missing" and "user 7 missing" share a fingerprint.
+Fields attached with With are ignored for the same reason, and errors built
+with ErrKey hash their MessageKey instead of the resolved message.
+
+With Config.FingerprintLocation and Config.StackTraces enabled, the function
+that constructed each ErrnieError is hashed as well. Fingerprint returns an
+empty string for a nil error.
+*/
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	digest := fingerprintDigest(err)
+
+	return hex.EncodeToString(digest[:])
+}
+
+/*
+fingerprintDigest returns the big-endian fingerprint bytes for err without
+allocating, for emission as a log field.
+*/
+func fingerprintDigest(err error) [8]byte {
+	var digest [8]byte
+
+	binary.BigEndian.PutUint64(digest[:], fingerprintChain(fingerprintOffset, err))
+
+	return digest
+}
+
+/*
+fingerprintChain folds every error in err's chain into hash.
+*/
+func fingerprintChain(hash uint64, err error) uint64 {
+	for err != nil {
+		if target, ok := err.(*ErrnieError); ok {
+			if target == nil {
+				return hash
+			}
+
+			hash = fingerprintString(hash, KindName(target.Kind))
+			hash = fingerprintByte(hash, 0)
+			hash = fingerprintString(hash, string(target.Code))
+			hash = fingerprintByte(hash, 0)
+			hash = fingerprintString(hash, target.Op)
+			hash = fingerprintByte(hash, 0)
+			if target.MessageKey != "" {
+				hash = fingerprintString(hash, target.MessageKey)
+			} else {
+				hash = fingerprintMessage(hash, target.Message)
+			}
+
+			hash = fingerprintByte(hash, 0)
+
+			if fingerprintLocation.Load() && target.stack != nil {
+				hash = fingerprintString(hash, target.stack.origin())
+				hash = fingerprintByte(hash, 0)
+			}
+
+			err = target.Cause
+
+			continue
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			hash = fingerprintByte(hash, '[')
+
+			for _, child := range joined.Unwrap() {
+				hash = fingerprintChain(hash, child)
+				hash = fingerprintByte(hash, ',')
+			}
+
+			return fingerprintByte(hash, ']')
+		}
+
+		if info, ok := LookupKind(err); ok {
+			return fingerprintString(hash, info.Name)
+		}
+
+		hash = fingerprintMessage(hash, err.Error())
+		hash = fingerprintByte(hash, 0)
+		err = errors.Unwrap(err)
+	}
+
+	return hash
+}
+
+/*
+fingerprintMessage hashes message with its variable parts normalized: a word
+containing a digit hashes as "#" and a quoted string as "?". Words are split on
+whitespace and the punctuation that usually surrounds identifiers, so
+"/users/42" normalizes to "/users/#".
+*/
+func fingerprintMessage(hash uint64, message string) uint64 {
+	for index := 0; index < len(message); {
+		char := message[index]
+
+		if fingerprintQuote(char) && (index == 0 || fingerprintSeparator(message[index-1])) {
+			if end := strings.IndexByte(message[index+1:], char); end >= 0 {
+				hash = fingerprintByte(hash, '?')
+				index += end + 2
+
+				continue
+			}
+		}
+
+		if fingerprintSeparator(char) {
+			hash = fingerprintByte(hash, char)
+			index++
+
+			continue
+		}
+
+		end := index + 1
+		digits := '0' <= char && char <= '9'
+
+		for end < len(message) && !fingerprintSeparator(message[end]) {
+			digits = digits || ('0' <= message[end] && message[end] <= '9')
+			end++
+		}
+
+		if digits {
+			hash = fingerprintByte(hash, '#')
+		} else {
+			hash = fingerprintString(hash, message[index:end])
+		}
+
+		index = end
+	}
+
+	return hash
+}
+
+/*
+fingerprintSeparator reports whether char ends a word during normalization.
+*/
+func fingerprintSeparator(char byte) bool {
+	switch char {
+	case ' ', '\t', '\n', '\r', ':', ',', ';', '=', '/', '(', ')', '[', ']', '{', '}', '<', '>':
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+fingerprintQuote reports whether char opens a quoted string.
+*/
+func fingerprintQuote(char byte) bool {
+	return char == '"' || char == '\'' || char == '`'
+}
+
+func fingerprintString(hash uint64, value string) uint64 {
+	for index := 0; index < len(value); index++ {
+		hash = fingerprintByte(hash, value[index])
+	}
+
+	return hash
+}
+
+func fingerprintByte(hash uint64, char byte) uint64 {
+	return (hash ^ uint64(char)) * fingerprintPrime
+}
+
+/*
+origin returns the function that constructed the error, the first recorded
+frame, without its line number so fingerprints survive unrelated edits.
+*/
+func (trace *stackTrace) origin() string {
+	if trace == nil || len(trace.pcs) == 0 {
+		return ""
+	}
+
+	frame, _ := runtime.CallersFrames(trace.pcs[:1]).Next()
+
+	return frame.Function
+}
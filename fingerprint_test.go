@@ -0,0 +1,158 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkFingerprintSink string
+
+/*
+enableTestFingerprintLocation turns constructor locations in fingerprints on
+for the duration of a test.
+*/
+func enableTestFingerprintLocation(t *testing.T) {
+	t.Helper()
+
+	previous := fingerprintLocation.Load()
+	fingerprintLocation.Store(true)
+
+	t.Cleanup(func() {
+		fingerprintLocation.Store(previous)
+	})
+}
+
+func fingerprintFromLoad() *ErrnieError {
+	return Err(NotFound, "user missing", nil)
+}
+
+func fingerprintFromSave() *ErrnieError {
+	return Err(NotFound, "user missing", nil)
+}
+
+/*
+TestFingerprint verifies stable grouping identifiers for error chains.
+*/
+func TestFingerprint(t *testing.T) {
+	Convey("Given errors that differ only in variable message parts", t, func() {
+		first := Err(NotFound, "user 42 missing from /tenants/7/users", nil).Operation("user.load")
+		second := Err(NotFound, "user 1337 missing from /tenants/9/users", nil).Operation("user.load")
+		quoted := Err(Validation, `email "a@example.com" is invalid`, nil)
+		otherQuoted := Err(Validation, `email 'b@example.com' is invalid`, nil)
+
+		Convey("When they are fingerprinted", func() {
+			Convey("Then the fingerprints should match", func() {
+				So(Fingerprint(first), ShouldEqual, Fingerprint(second))
+				So(Fingerprint(quoted), ShouldEqual, Fingerprint(otherQuoted))
+				So(Fingerprint(first), ShouldHaveLength, 16)
+			})
+		})
+	})
+
+	Convey("Given errors that differ in Kind, Op, or wording", t, func() {
+		base := Err(NotFound, "user missing", nil).Operation("user.load")
+
+		Convey("When they are fingerprinted", func() {
+			Convey("Then the fingerprints should differ", func() {
+				So(Fingerprint(base), ShouldNotEqual, Fingerprint(Err(Internal, "user missing", nil).Operation("user.load")))
+				So(Fingerprint(base), ShouldNotEqual, Fingerprint(Err(NotFound, "user missing", nil).Operation("user.save")))
+				So(Fingerprint(base), ShouldNotEqual, Fingerprint(Err(NotFound, "user gone", nil).Operation("user.load")))
+			})
+		})
+	})
+
+	Convey("Given errors that differ only in fields", t, func() {
+		first := Err(IO, "write failed", nil).With("path", "/tmp/a")
+		second := Err(IO, "write failed", nil).With("path", "/tmp/b", "attempt", 3)
+
+		Convey("When they are fingerprinted", func() {
+			Convey("Then the fingerprints should match", func() {
+				So(Fingerprint(first), ShouldEqual, Fingerprint(second))
+			})
+		})
+	})
+
+	Convey("Given wrapped and joined chains", t, func() {
+		wrapped := fmt.Errorf("load 12: %w", Err(Timeout, "db slow", context.DeadlineExceeded))
+		otherWrapped := fmt.Errorf("load 99: %w", Err(Timeout, "db slow", context.DeadlineExceeded))
+		joined := Combine(Err(IO, "close a", nil), Err(Internal, "rollback", nil))
+		swapped := Combine(Err(Internal, "rollback", nil), Err(IO, "close a", nil))
+
+		Convey("When they are fingerprinted", func() {
+			Convey("Then every node should contribute", func() {
+				So(Fingerprint(wrapped), ShouldEqual, Fingerprint(otherWrapped))
+				So(Fingerprint(wrapped), ShouldNotEqual, Fingerprint(Err(Timeout, "db slow", nil)))
+				So(Fingerprint(joined), ShouldNotEqual, Fingerprint(swapped))
+				So(Fingerprint(joined), ShouldNotEqual, Fingerprint(Err(IO, "close a", nil)))
+			})
+		})
+	})
+
+	Convey("Given a nil error", t, func() {
+		Convey("When it is fingerprinted", func() {
+			Convey("Then the fingerprint should be empty", func() {
+				So(Fingerprint(nil), ShouldEqual, "")
+			})
+		})
+	})
+
+	Convey("Given identical errors built in different functions", t, func() {
+		enableTestStackTraces(t)
+
+		Convey("When locations are disabled", func() {
+			Convey("Then the fingerprints should match", func() {
+				So(Fingerprint(fingerprintFromLoad()), ShouldEqual, Fingerprint(fingerprintFromSave()))
+			})
+		})
+
+		Convey("When locations are enabled", func() {
+			enableTestFingerprintLocation(t)
+
+			Convey("Then the fingerprints should differ by constructor", func() {
+				So(Fingerprint(fingerprintFromLoad()), ShouldNotEqual, Fingerprint(fingerprintFromSave()))
+				So(Fingerprint(fingerprintFromLoad()), ShouldEqual, Fingerprint(fingerprintFromLoad()))
+			})
+		})
+	})
+
+	Convey("Given an error logged through Error", t, func() {
+		buffer := configureTestLogger(t, log.ErrorLevel)
+		err := Err(NotFound, "user 42 missing", nil)
+		plain := errors.New("plain failure")
+
+		Convey("When both an ErrnieError and a plain error are logged", func() {
+			Error(err)
+			Error(plain)
+
+			Convey("Then each line should carry the fingerprint field", func() {
+				So(buffer.String(), ShouldContainSubstring, `"fingerprint":"`+Fingerprint(err)+`"`)
+				So(buffer.String(), ShouldContainSubstring, `"fingerprint":"`+Fingerprint(plain)+`"`)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkFingerprint measures fingerprinting a wrapped ErrnieError.
+*/
+func BenchmarkFingerprint(b *testing.B) {
+	err := fmt.Errorf("load: %w", Err(NotFound, "user 42 missing", nil).Operation("user.load"))
+
+	b.Run("string", func(b *testing.B) {
+		for range b.N {
+			benchmarkFingerprintSink = Fingerprint(err)
+		}
+	})
+
+	b.Run("digest", func(b *testing.B) {
+		for range b.N {
+			digest := fingerprintDigest(err)
+			benchmarkErrnieBoolSink = digest[0] == 0
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package errnie
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+treeIndent is the indentation added per level of the %+v error tree.
+*/
+const treeIndent = "  "
+
+/*
+joinedErrors joins three or more errors for Combine. It matches errors.Join,
+newline-separated text and Unwrap() []error, and adds the %+v tree format.
+*/
+type joinedErrors struct {
+	unwrapped []error
+}
+
+func (joined *joinedErrors) Error() string {
+	texts := make([]string, len(joined.unwrapped))
+
+	for index, err := range joined.unwrapped {
+		texts[index] = err.Error()
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+func (joined *joinedErrors) Unwrap() []error {
+	return joined.unwrapped
+}
+
+/*
+Format implements fmt.Formatter with the same verbs as ErrnieError.
+*/
+func (joined *joinedErrors) Format(state fmt.State, verb rune) {
+	formatError(state, verb, joined)
+}
+
+/*
+Format implements fmt.Formatter with the same verbs as ErrnieError.
+*/
+func (joined joinedPair) Format(state fmt.State, verb rune) {
+	formatError(state, verb, joined)
+}
+
+/*
+formatError implements the shared fmt.Formatter verbs: %v and %s print
+Error(), %q quotes it, and %+v writes the indented error tree.
+*/
+func formatError(state fmt.State, verb rune, err error) {
+	switch {
+	case verb == 'q':
+		fmt.Fprintf(state, "%q", err.Error())
+	case verb == 'v' && state.Flag('+'):
+		writeErrorTree(state, err, "", "")
+	default:
+		io.WriteString(state, err.Error())
+	}
+}
+
+/*
+writeErrorTree writes err and its causes as an indented tree. first prefixes
+the node's headline and rest prefixes its detail lines and children. Each
+ErrnieError shows its Kind, Op, message, fields, and, when a stack trace was
+recorded for it, the location that constructed it:
+
+	[internal] shutdown: cleanup failed
+	  fields: attempt=2
+	  at main.shutdown (cmd/main.go:40)
+	  cause: 3 joined errors
+	    - [io] file.close: close a
+	    - [io] conn.close: close b
+	    - broken pipe
+*/
+func writeErrorTree(writer io.Writer, err error, first, rest string) {
+	if target, ok := err.(*ErrnieError); ok && target != nil {
+		io.WriteString(writer, first+target.headline())
+
+		if fields := target.Fields(); len(fields) > 0 {
+			io.WriteString(writer, "\n"+rest+treeIndent+"fields:")
+			policy := redactionPolicySnapshot()
+
+			for index := 0; index+1 < len(fields); index += 2 {
+				key, _ := fields[index].(string)
+				value, _ := policy.redact(key, fields[index+1])
+				fmt.Fprintf(writer, " %s=%v", key, value)
+			}
+		}
+
+		if frames := target.stack.Frames(); len(frames) > 0 {
+			io.WriteString(writer, "\n"+rest+treeIndent+"at "+frames[0].Function+
+				" ("+frames[0].File+":"+strconv.Itoa(frames[0].Line)+")")
+		}
+
+		if target.Cause != nil {
+			io.WriteString(writer, "\n")
+			writeErrorTree(writer, target.Cause, rest+treeIndent+"cause: ", rest+treeIndent)
+		}
+
+		return
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		children := joined.Unwrap()
+		io.WriteString(writer, first+strconv.Itoa(len(children))+" joined errors")
+
+		for _, child := range children {
+			io.WriteString(writer, "\n")
+			writeErrorTree(writer, child, rest+treeIndent+"- ", rest+treeIndent+treeIndent)
+		}
+
+		return
+	}
+
+	io.WriteString(writer, first+strings.ReplaceAll(err.Error(), "\n", "\n"+rest+treeIndent))
+
+	if cause := errors.Unwrap(err); cause != nil {
+		io.WriteString(writer, "\n")
+		writeErrorTree(writer, cause, rest+treeIndent+"cause: ", rest+treeIndent)
+	}
+}
+
+/*
+headline renders the first line of an error tree node: the Kind name in
+brackets, then Op and Message as Error() joins them, without fields.
+*/
+func (err *ErrnieError) headline() string {
+	line := "[" + KindName(err.Kind) + "]"
+	message := err.message()
+
+	switch {
+	case err.Op != "" && message != "":
+		line += " " + err.Op + ": " + message
+	case err.Op != "":
+		line += " " + err.Op
+	case message != "":
+		line += " " + message
+	}
+
+	return line
+}
@@ -0,0 +1,104 @@
+package errnie
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+TestErrorTreeFormat verifies the %+v tree rendering of errors and joins.
+*/
+func TestErrorTreeFormat(t *testing.T) {
+	Convey("Given a Combine of three failed cleanups under an ErrnieError", t, func() {
+		cleanups := Combine(
+			Err(IO, "close a", nil).Operation("file.close").With("path", "/tmp/a"),
+			Err(Timeout, "", nil).Operation("conn.close"),
+			errors.New("broken pipe"),
+		)
+		err := Err(Internal, "cleanup failed", cleanups).Operation("shutdown").With("attempt", 2)
+
+		Convey("When it is formatted with %+v", func() {
+			text := fmt.Sprintf("%+v", err)
+
+			Convey("Then every branch should show its own Kind, Op, and fields", func() {
+				So(text, ShouldEqual, strings.Join([]string{
+					"[internal] shutdown: cleanup failed",
+					"  fields: attempt=2",
+					"  cause: 3 joined errors",
+					"    - [io] file.close: close a",
+					"        fields: path=/tmp/a",
+					"    - [timeout] conn.close",
+					"    - broken pipe",
+				}, "\n"))
+			})
+		})
+
+		Convey("When the join itself is formatted", func() {
+			Convey("Then %v should match errors.Join text and %+v should be a tree", func() {
+				So(fmt.Sprintf("%v", cleanups), ShouldEqual, "file.close: close a path=/tmp/a\nconn.close: timeout\nbroken pipe")
+				So(fmt.Sprintf("%s", cleanups), ShouldEqual, cleanups.Error())
+				So(fmt.Sprintf("%+v", cleanups), ShouldStartWith, "3 joined errors\n  - [io] file.close: close a")
+			})
+		})
+	})
+
+	Convey("Given a pair join wrapped by fmt.Errorf", t, func() {
+		err := fmt.Errorf("flush: %w", Combine(Err(IO, "disk full", nil), Err(Canceled, "stopped", nil)))
+
+		Convey("When the pair is formatted", func() {
+			pair := errors.Unwrap(err)
+
+			Convey("Then %q should quote the text and %+v should be a tree", func() {
+				So(fmt.Sprintf("%q", pair), ShouldEqual, `"disk full\nstopped"`)
+				So(fmt.Sprintf("%+v", pair), ShouldEqual, "2 joined errors\n  - [io] disk full\n  - [canceled] stopped")
+			})
+		})
+
+		Convey("When an ErrnieError wraps it", func() {
+			text := fmt.Sprintf("%+v", Err(Internal, "", err))
+
+			Convey("Then plain wrappers should show their cause below them", func() {
+				So(text, ShouldEqual, strings.Join([]string{
+					"[internal]",
+					"  cause: flush: disk full",
+					"    stopped",
+					"    cause: 2 joined errors",
+					"      - [io] disk full",
+					"      - [canceled] stopped",
+				}, "\n"))
+			})
+		})
+	})
+
+	Convey("Given a joined error with stack traces enabled", t, func() {
+		enableTestStackTraces(t)
+		err := Combine(Err(IO, "a", nil), Err(IO, "b", nil), Err(IO, "c", nil))
+
+		Convey("When it is formatted with %+v", func() {
+			text := fmt.Sprintf("%+v", err)
+
+			Convey("Then each node should show where it was constructed", func() {
+				So(strings.Count(text, "\n      at "), ShouldEqual, 3)
+				So(text, ShouldContainSubstring, "TestErrorTreeFormat")
+				So(text, ShouldContainSubstring, "format_test.go:")
+			})
+		})
+	})
+
+	Convey("Given Combine with more than two errors", t, func() {
+		first := Err(NotFound, "missing", nil)
+		err := Combine(first, errors.New("b"), errors.New("c"))
+
+		Convey("When the chain is inspected", func() {
+			Convey("Then it should behave like errors.Join", func() {
+				So(errors.Is(err, first), ShouldBeTrue)
+				So(IsNotFound(err), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "missing\nb\nc")
+			})
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package errnie
+
+import (
+	"context"
+)
+
+/*
+Future is the pending Result of a function started with DoesAsync. It is safe
+to Await from several goroutines, and every caller sees the same Result.
+*/
+type Future[T any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	result Result[T]
+}
+
+/*
+DoesAsync starts fn in a new goroutine and returns its Future. fn receives a
+context derived from ctx, so cancelling ctx or calling Cancel reaches it. A
+panic in fn completes the Future with an Internal error (see Recover).
+
+	user := errnie.DoesAsync(ctx, users.Find)
+	orders := errnie.DoesAsync(ctx, orders.Recent)
+
+	if err := user.Await(ctx).Err(); err != nil {
+		orders.Cancel()
+		return err
+	}
+*/
+func DoesAsync[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+
+	future := &Future[T]{done: make(chan struct{}), cancel: cancel}
+
+	go func() {
+		defer close(future.done)
+		defer cancel()
+
+		var value T
+
+		err := runRecovered(ctx, func(ctx context.Context) (err error) {
+			value, err = fn(ctx)
+			return err
+		})
+
+		future.result = Result[T]{value: value, err: err}
+	}()
+
+	return future
+}
+
+/*
+Await blocks until the Future completes or ctx is done. When ctx ends first,
+the Result fails with a Canceled or DeadlineExceeded ErrnieError with Op
+"future.await" and fn keeps running; call Cancel to stop it.
+*/
+func (future *Future[T]) Await(ctx context.Context) Result[T] {
+	select {
+	case <-future.done:
+		return future.result
+	default:
+	}
+
+	select {
+	case <-future.done:
+		return future.result
+	case <-ctx.Done():
+		return Result[T]{err: abandonedError(ctx, "future.await")}
+	}
+}
+
+/*
+Done returns a channel that is closed when the Future completes.
+*/
+func (future *Future[T]) Done() <-chan struct{} {
+	return future.done
+}
+
+/*
+Cancel cancels the context passed to fn. It does not wait for fn to return.
+*/
+func (future *Future[T]) Cancel() {
+	future.cancel()
+}
+
+/*
+AwaitAll waits for every future and returns their values in argument order.
+Failures are joined with Combine, each wrapped with an "index" field naming
+its position and keeping its Kind; a failed future leaves its value, usually
+the zero value, in its slot.
+*/
+func AwaitAll[T any](ctx context.Context, futures ...*Future[T]) Result[[]T] {
+	values := make([]T, len(futures))
+
+	var failures []error
+
+	for index, future := range futures {
+		result := future.Await(ctx)
+		values[index] = result.value
+
+		if result.err != nil {
+			failures = append(failures, indexedError(result.err, index))
+		}
+	}
+
+	return Result[[]T]{value: values, err: Combine(failures...)}
+}
+
+/*
+AwaitAny returns the first future to succeed and cancels the others. When
+every future fails, the failures are joined like AwaitAll, in argument order.
+Without futures it fails with Validation.
+*/
+func AwaitAny[T any](ctx context.Context, futures ...*Future[T]) Result[T] {
+	if len(futures) == 0 {
+		return Result[T]{err: Err(Validation, "no futures to await", nil).Operation("future.await_any")}
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	completed := completions(futures, stop)
+	failures := make([]error, len(futures))
+
+	for range futures {
+		select {
+		case index := <-completed:
+			result := futures[index].result
+
+			if result.err == nil {
+				for _, other := range futures {
+					other.Cancel()
+				}
+
+				return result
+			}
+
+			failures[index] = indexedError(result.err, index)
+		case <-ctx.Done():
+			failures = append(failures, abandonedError(ctx, "future.await_any"))
+
+			return Result[T]{err: Combine(failures...)}
+		}
+	}
+
+	return Result[T]{err: Combine(failures...)}
+}
+
+/*
+indexedError wraps err with its position in a batch, keeping its Kind.
+*/
+func indexedError(err error, index int) *ErrnieError {
+	return Err(kindOf(err), "", err).With("index", index)
+}
+
+/*
+completions sends the index of each future as it completes, until stop is
+closed. The channel is buffered so senders never block.
+*/
+func completions[T any](futures []*Future[T], stop <-chan struct{}) <-chan int {
+	completed := make(chan int, len(futures))
+
+	for index, future := range futures {
+		go func() {
+			select {
+			case <-future.done:
+				completed <- index
+			case <-stop:
+			}
+		}()
+	}
+
+	return completed
+}
+
+/*
+abandonedError reports that ctx ended before the awaited work completed.
+*/
+func abandonedError(ctx context.Context, op string) *ErrnieError {
+	return Err(kindOf(ctx.Err()), "await abandoned", context.Cause(ctx)).Operation(op)
+}
@@ -0,0 +1,243 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkFutureSink Result[int]
+
+/*
+benchmarkAsyncFn is a named task for BenchmarkDoesAsync.
+*/
+func benchmarkAsyncFn(context.Context) (int, error) {
+	return 42, nil
+}
+
+/*
+TestDoesAsync verifies Future completion, cancellation, and panic capture.
+*/
+func TestDoesAsync(t *testing.T) {
+	Convey("Given a function started with DoesAsync", t, func() {
+		future := DoesAsync(context.Background(), func(context.Context) (string, error) {
+			return "ernie", nil
+		})
+
+		Convey("When it is awaited twice", func() {
+			first := future.Await(context.Background())
+			second := future.Await(context.Background())
+
+			Convey("Then both callers should see the same Result", func() {
+				So(first.Value(), ShouldEqual, "ernie")
+				So(first.Err(), ShouldBeNil)
+				So(second.Value(), ShouldEqual, "ernie")
+			})
+		})
+	})
+
+	Convey("Given a slow function", t, func() {
+		started := make(chan struct{})
+		future := DoesAsync(context.Background(), func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+
+		Convey("When the awaiting context expires first", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+
+			result := future.Await(ctx)
+
+			Convey("Then Await should give up without stopping the function", func() {
+				So(IsDeadlineExceeded(result.Err()), ShouldBeTrue)
+				So(result.Err().Error(), ShouldStartWith, "future.await: await abandoned")
+
+				select {
+				case <-future.Done():
+					t.Error("future completed before Cancel")
+				default:
+				}
+
+				future.Cancel()
+			})
+		})
+
+		Convey("When the Future is cancelled", func() {
+			<-started
+			future.Cancel()
+			result := future.Await(context.Background())
+
+			Convey("Then the function should see the cancellation", func() {
+				So(errors.Is(result.Err(), context.Canceled), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a parent context that is cancelled", t, func() {
+		parent, cancel := context.WithCancel(context.Background())
+		future := DoesAsync(parent, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+
+		Convey("When the parent is cancelled", func() {
+			cancel()
+
+			Convey("Then the cancellation should propagate to the function", func() {
+				So(errors.Is(future.Await(context.Background()).Err(), context.Canceled), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a function that panics", t, func() {
+		future := DoesAsync(context.Background(), func(context.Context) (int, error) {
+			panic("lookup exploded")
+		})
+
+		Convey("When it is awaited", func() {
+			result := future.Await(context.Background())
+
+			Convey("Then the panic should be an Internal error", func() {
+				target, _ := AsErrnie(result.Err())
+				So(IsInternal(result.Err()), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"panic", "lookup exploded"})
+			})
+		})
+	})
+}
+
+/*
+TestAwaitAll verifies ordered collection of several futures.
+*/
+func TestAwaitAll(t *testing.T) {
+	Convey("Given futures that finish out of order and one that fails", t, func() {
+		futures := make([]*Future[int], 4)
+
+		for index := range futures {
+			futures[index] = DoesAsync(context.Background(), func(context.Context) (int, error) {
+				time.Sleep(time.Duration(4-index) * time.Millisecond)
+
+				if index == 2 {
+					return 0, Err(NotFound, "missing", nil)
+				}
+
+				return index * 10, nil
+			})
+		}
+
+		Convey("When AwaitAll is called", func() {
+			result := AwaitAll(context.Background(), futures...)
+
+			Convey("Then values should be in argument order and the failure indexed", func() {
+				So(result.Value(), ShouldResemble, []int{0, 10, 0, 30})
+				So(result.Err().Error(), ShouldEqual, "missing index=2")
+				So(IsNotFound(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given no futures", t, func() {
+		Convey("When AwaitAll is called", func() {
+			result := AwaitAll[int](context.Background())
+
+			Convey("Then it should succeed with no values", func() {
+				So(result.Err(), ShouldBeNil)
+				So(result.Value(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+/*
+TestAwaitAny verifies first-success selection and loser cancellation.
+*/
+func TestAwaitAny(t *testing.T) {
+	Convey("Given a fast failure, a fast success, and a slow future", t, func() {
+		slowCancelled := make(chan struct{})
+
+		failing := DoesAsync(context.Background(), func(context.Context) (string, error) {
+			return "", Err(ServiceUnavailable, "primary down", nil)
+		})
+
+		winning := DoesAsync(context.Background(), func(context.Context) (string, error) {
+			<-failing.Done()
+			return "replica", nil
+		})
+
+		slow := DoesAsync(context.Background(), func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			close(slowCancelled)
+			return "", ctx.Err()
+		})
+
+		Convey("When AwaitAny is called", func() {
+			result := AwaitAny(context.Background(), failing, winning, slow)
+
+			Convey("Then the first success should win and the rest be cancelled", func() {
+				So(result.Err(), ShouldBeNil)
+				So(result.Value(), ShouldEqual, "replica")
+
+				select {
+				case <-slowCancelled:
+				case <-time.After(time.Second):
+					t.Error("slow future was not cancelled")
+				}
+			})
+		})
+	})
+
+	Convey("Given futures that all fail", t, func() {
+		first := DoesAsync(context.Background(), func(context.Context) (int, error) {
+			return 0, Err(Timeout, "a timed out", nil)
+		})
+
+		second := DoesAsync(context.Background(), func(context.Context) (int, error) {
+			<-first.Done()
+			return 0, Err(IO, "b failed", nil)
+		})
+
+		Convey("When AwaitAny is called", func() {
+			result := AwaitAny(context.Background(), first, second)
+
+			Convey("Then every failure should be joined in argument order", func() {
+				So(result.Err().Error(), ShouldEqual, "a timed out index=0\nb failed index=1")
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+				So(IsIO(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given no futures or an expired context", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		pending := DoesAsync(context.Background(), func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		defer pending.Cancel()
+
+		Convey("When AwaitAny is called", func() {
+			Convey("Then it should fail with Validation or Canceled", func() {
+				So(IsValidation(AwaitAny[int](context.Background()).Err()), ShouldBeTrue)
+				So(IsCanceled(AwaitAny(ctx, pending).Err()), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkDoesAsync measures starting and awaiting a Future.
+*/
+func BenchmarkDoesAsync(b *testing.B) {
+	ctx := context.Background()
+
+	for range b.N {
+		benchmarkFutureSink = DoesAsync(ctx, benchmarkAsyncFn).Await(ctx)
+	}
+}
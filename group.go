@@ -0,0 +1,210 @@
+package errnie
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+GroupConfig configures a Group. The zero value runs every task at once and
+cancels on the first error.
+*/
+type GroupConfig struct {
+	// Limit bounds the number of tasks running at once; Go blocks while the
+	// limit is reached. Zero or less means no limit.
+	Limit int
+	// Cancel decides whether a task's error cancels the shared context.
+	// Defaults to cancelling on every error. See CancelOnKinds and
+	// CancelExceptKinds.
+	Cancel func(error) bool
+}
+
+/*
+Group runs tasks concurrently under a shared context, like errgroup, but
+decides by Kind whether a failure cancels the others and reports every
+failure instead of only the first. Each task's error is wrapped in an
+ErrnieError whose Op is the task name, keeping the Kind, and a panicking task
+fails with an Internal error instead of crashing the process.
+
+	group, ctx := errnie.NewGroup(ctx, errnie.GroupConfig{
+		Limit:  8,
+		Cancel: errnie.CancelExceptKinds(errnie.NotFound),
+	})
+
+	for _, id := range ids {
+		group.Go("user.sync", func(ctx context.Context) error {
+			return syncUser(ctx, id)
+		})
+	}
+
+	return group.Wait()
+*/
+type Group struct {
+	ctx       context.Context
+	cancel    context.CancelCauseFunc
+	config    GroupConfig
+	tokens    chan struct{}
+	workers   sync.WaitGroup
+	cancelled atomic.Bool
+	mutex     sync.Mutex
+	errs      []error
+}
+
+/*
+NewGroup creates a Group and the context its tasks receive. The context is
+cancelled when a task's error passes config.Cancel, with that error as its
+cause (see context.Cause), or when Wait returns.
+*/
+func NewGroup(ctx context.Context, config GroupConfig) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	if config.Cancel == nil {
+		config.Cancel = cancelOnAny
+	}
+
+	group := &Group{ctx: ctx, cancel: cancel, config: config}
+
+	if config.Limit > 0 {
+		group.tokens = make(chan struct{}, config.Limit)
+	}
+
+	return group, ctx
+}
+
+/*
+Go starts fn in a new goroutine under the name op, blocking first while
+Limit tasks are running. Tasks that fail with a context error after the Group
+cancelled itself are not reported, because they only echo the failure that
+caused the cancellation.
+*/
+func (group *Group) Go(op string, fn func(context.Context) error) {
+	group.mutex.Lock()
+	index := len(group.errs)
+	group.errs = append(group.errs, nil)
+	group.mutex.Unlock()
+
+	if group.tokens != nil {
+		group.tokens <- struct{}{}
+	}
+
+	group.workers.Go(func() {
+		if group.tokens != nil {
+			defer func() { <-group.tokens }()
+		}
+
+		err := runRecovered(group.ctx, fn)
+		if err == nil || (group.cancelled.Load() && IsContext(err)) {
+			return
+		}
+
+		if op != "" {
+			err = Err(kindOf(err), "", err).Operation(op)
+		}
+
+		group.mutex.Lock()
+		group.errs[index] = err
+		group.mutex.Unlock()
+
+		if group.config.Cancel(err) && group.cancelled.CompareAndSwap(false, true) {
+			group.cancel(err)
+		}
+	})
+}
+
+/*
+Wait blocks until every task has returned, cancels the Group's context, and
+returns the task errors joined with Combine in the order the tasks were
+started, or nil when all of them succeeded.
+*/
+func (group *Group) Wait() error {
+	group.workers.Wait()
+	group.cancel(nil)
+
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	return Combine(group.errs...)
+}
+
+/*
+CancelOnKinds returns a GroupConfig.Cancel policy that cancels only on errors
+of the given kinds.
+*/
+func CancelOnKinds(kinds ...Kind) func(error) bool {
+	return func(err error) bool {
+		return slices.Contains(kinds, kindOf(err))
+	}
+}
+
+/*
+CancelExceptKinds returns a GroupConfig.Cancel policy that cancels on every
+error except those of the given kinds.
+*/
+func CancelExceptKinds(kinds ...Kind) func(error) bool {
+	return func(err error) bool {
+		return !slices.Contains(kinds, kindOf(err))
+	}
+}
+
+/*
+cancelOnAny is the default GroupConfig.Cancel policy.
+*/
+func cancelOnAny(error) bool {
+	return true
+}
+
+/*
+ResultGroup is a Group whose tasks return a Result[T]. Wait collects the
+values in the order the tasks were started, so results line up with inputs.
+*/
+type ResultGroup[T any] struct {
+	group  *Group
+	mutex  sync.Mutex
+	values []T
+}
+
+/*
+NewResultGroup creates a ResultGroup and the context its tasks receive, with
+the same semantics as NewGroup.
+*/
+func NewResultGroup[T any](ctx context.Context, config GroupConfig) (*ResultGroup[T], context.Context) {
+	group, ctx := NewGroup(ctx, config)
+
+	return &ResultGroup[T]{group: group}, ctx
+}
+
+/*
+Go starts fn under the name op like Group.Go and records its value.
+*/
+func (group *ResultGroup[T]) Go(op string, fn func(context.Context) Result[T]) {
+	group.mutex.Lock()
+	index := len(group.values)
+	group.values = append(group.values, *new(T))
+	group.mutex.Unlock()
+
+	group.group.Go(op, func(ctx context.Context) error {
+		result := fn(ctx)
+
+		group.mutex.Lock()
+		group.values[index] = result.value
+		group.mutex.Unlock()
+
+		return result.err
+	})
+}
+
+/*
+Wait blocks until every task has returned and yields the values in start
+order together with the joined error from Group.Wait. A failed task leaves
+the value it returned, usually the zero value, in its slot.
+*/
+func (group *ResultGroup[T]) Wait() Result[[]T] {
+	err := group.group.Wait()
+
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	return Result[[]T]{value: group.values, err: err}
+}
@@ -0,0 +1,213 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkGroupSink error
+
+/*
+TestGroup verifies concurrent tasks, Kind-aware cancellation, and reporting.
+*/
+func TestGroup(t *testing.T) {
+	Convey("Given a Group with a limit of two", t, func() {
+		group, _ := NewGroup(context.Background(), GroupConfig{Limit: 2})
+
+		Convey("When more tasks than the limit are started", func() {
+			var running, peak atomic.Int64
+
+			for range 8 {
+				group.Go("work", func(context.Context) error {
+					current := running.Add(1)
+
+					for {
+						previous := peak.Load()
+						if current <= previous || peak.CompareAndSwap(previous, current) {
+							break
+						}
+					}
+
+					time.Sleep(time.Millisecond)
+					running.Add(-1)
+
+					return nil
+				})
+			}
+
+			err := group.Wait()
+
+			Convey("Then no more than two should run at once", func() {
+				So(err, ShouldBeNil)
+				So(peak.Load(), ShouldBeBetweenOrEqual, 1, 2)
+			})
+		})
+	})
+
+	Convey("Given a Group that ignores NotFound", t, func() {
+		group, ctx := NewGroup(context.Background(), GroupConfig{
+			Cancel: CancelExceptKinds(NotFound),
+		})
+
+		Convey("When one task misses and another fails internally", func() {
+			missed := make(chan struct{})
+
+			group.Go("user.load", func(context.Context) error {
+				defer close(missed)
+				return Err(NotFound, "user missing", nil)
+			})
+
+			group.Go("user.store", func(ctx context.Context) error {
+				<-missed
+
+				if ctx.Err() != nil {
+					return Err(Internal, "cancelled by a miss", nil)
+				}
+
+				return Err(Internal, "write failed", nil)
+			})
+
+			group.Go("user.index", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+			err := group.Wait()
+
+			Convey("Then only the Internal error should cancel the others", func() {
+				So(context.Cause(ctx).Error(), ShouldEqual, "user.store: write failed")
+			})
+
+			Convey("Then every real failure should be reported with its Op, in start order", func() {
+				branches := err.(interface{ Unwrap() []error }).Unwrap()
+
+				So(branches, ShouldHaveLength, 2)
+				So(branches[0].Error(), ShouldEqual, "user.load: user missing")
+				So(branches[1].Error(), ShouldEqual, "user.store: write failed")
+				So(IsNotFound(err), ShouldBeTrue)
+				So(IsInternal(err), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a Group that cancels only on Internal", t, func() {
+		group, ctx := NewGroup(context.Background(), GroupConfig{
+			Cancel: CancelOnKinds(Internal),
+		})
+
+		Convey("When a task fails with Validation", func() {
+			group.Go("", func(context.Context) error {
+				return Err(Validation, "bad input", nil)
+			})
+
+			err := group.Wait()
+
+			Convey("Then the context should only be cancelled by Wait", func() {
+				So(err.Error(), ShouldEqual, "bad input")
+				So(context.Cause(ctx), ShouldEqual, context.Canceled)
+			})
+		})
+	})
+
+	Convey("Given a Group whose task panics", t, func() {
+		group, _ := NewGroup(context.Background(), GroupConfig{})
+
+		Convey("When Wait returns", func() {
+			group.Go("worker", func(context.Context) error {
+				panic("boom")
+			})
+
+			err := group.Wait()
+
+			Convey("Then the panic should be an Internal error under the task's Op", func() {
+				target, _ := AsErrnie(err)
+				So(IsInternal(err), ShouldBeTrue)
+				So(target.Op, ShouldEqual, "worker")
+			})
+		})
+	})
+
+	Convey("Given a Group under a parent context that is cancelled", t, func() {
+		parent, cancel := context.WithCancel(context.Background())
+		group, _ := NewGroup(parent, GroupConfig{})
+
+		Convey("When a task returns the context error", func() {
+			cancel()
+
+			group.Go("poll", func(ctx context.Context) error {
+				return ctx.Err()
+			})
+
+			err := group.Wait()
+
+			Convey("Then it should be reported, since the Group did not cause it", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(IsCanceled(err), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+TestResultGroup verifies ordered value collection.
+*/
+func TestResultGroup(t *testing.T) {
+	Convey("Given a ResultGroup of typed tasks", t, func() {
+		group, _ := NewResultGroup[string](context.Background(), GroupConfig{
+			Limit:  3,
+			Cancel: CancelOnKinds(Internal),
+		})
+
+		Convey("When tasks finish out of order and one fails", func() {
+			for index := range 6 {
+				group.Go("item."+strconv.Itoa(index), func(context.Context) Result[string] {
+					time.Sleep(time.Duration(6-index) * time.Millisecond)
+
+					if index == 4 {
+						return Does(func() (string, error) {
+							return "", Err(NotFound, "missing", nil)
+						})
+					}
+
+					return Does(func() (string, error) {
+						return "value-" + strconv.Itoa(index), nil
+					})
+				})
+			}
+
+			result := group.Wait()
+
+			Convey("Then values should line up with the start order", func() {
+				So(result.Value(), ShouldResemble, []string{"value-0", "value-1", "value-2", "value-3", "", "value-5"})
+			})
+
+			Convey("Then the failure should carry the task's Op", func() {
+				So(result.Err().Error(), ShouldEqual, "item.4: missing")
+				So(IsNotFound(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkGroup measures starting and waiting on a batch of tasks.
+*/
+func BenchmarkGroup(b *testing.B) {
+	task := func(context.Context) error { return nil }
+
+	for range b.N {
+		group, _ := NewGroup(context.Background(), GroupConfig{Limit: 4})
+
+		for range 16 {
+			group.Go("task", task)
+		}
+
+		benchmarkGroupSink = group.Wait()
+	}
+}
@@ -122,3 +122,43 @@ func BenchmarkHotpathCombineCleanup(b *testing.B) {
 		hotpathSink = Combine(first, second)
 	}
 }
+
+/*
+BenchmarkHotpathCollectorAdd measures concurrent accumulation into a Collector.
+*/
+func BenchmarkHotpathCollectorAdd(b *testing.B) {
+	err := Err(Validation, "invalid input", nil)
+
+	b.Run("nil error", func(b *testing.B) {
+		collector := NewCollector(16)
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				collector.Add(nil)
+			}
+		})
+	})
+
+	b.Run("overflow", func(b *testing.B) {
+		collector := NewCollector(16)
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				collector.Add(err)
+			}
+		})
+	})
+
+	b.Run("finalize", func(b *testing.B) {
+		collector := NewCollector(16)
+
+		for range 32 {
+			collector.Add(err)
+		}
+
+		b.ResetTimer()
+		for range b.N {
+			hotpathSink = collector.Err()
+		}
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Package httperr maps errnie Kinds to HTTP status codes at the transport
+boundary and turns upstream HTTP failures back into ErrnieErrors.
+*/
+package httperr
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+StatusClientClosedRequest is the de facto status for requests abandoned by the
+client before a response was written. It is not registered with net/http; the
+Canceled Kind maps to it.
+*/
+const StatusClientClosedRequest = 499
+
+/*
+StatusFor returns the HTTP status code for err. It walks the chain with
+AsErrnie and maps the first ErrnieError's Kind through the Kind registry (see
+errnie.RegisterKind). A nil error maps to 200;
+Unknown and errors without an ErrnieError map to 500.
+*/
+func StatusFor(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	target, ok := errnie.AsErrnie(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	return statusForKind(target.Kind)
+}
+
+/*
+statusForKind returns the registered HTTP status of kind, defaulting to 500.
+*/
+func statusForKind(kind errnie.Kind) int {
+	if info, ok := errnie.LookupKind(kind); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+
+	return http.StatusInternalServerError
+}
+
+/*
+FromStatus turns an upstream HTTP response into an ErrnieError whose Kind
+matches code. The trimmed body becomes the message, falling back to the
+standard status text, and the code is attached as the "status" field. It
+returns nil for 2xx codes so callers can guard every response with it.
+*/
+func FromStatus(code int, body []byte) error {
+	if code >= 200 && code < 300 {
+		return nil
+	}
+
+	message := string(bytes.TrimSpace(body))
+
+	if message == "" {
+		message = http.StatusText(code)
+	}
+
+	return errnie.Err(kindForStatus(code), message, nil).With("status", code)
+}
+
+/*
+kindForStatus resolves code through the Kind registry. 408 is treated as
+Timeout; other unregistered 4xx codes map to BadRequest, unregistered 5xx codes
+to Internal, and anything else to Unknown.
+*/
+func kindForStatus(code int) errnie.Kind {
+	if code == http.StatusRequestTimeout {
+		return errnie.Timeout
+	}
+
+	if kind, ok := errnie.KindForHTTPStatus(code); ok {
+		return kind
+	}
+
+	switch {
+	case code >= 400 && code < 500:
+		return errnie.BadRequest
+	case code >= 500 && code < 600:
+		return errnie.Internal
+	default:
+		return errnie.Unknown
+	}
+}
@@ -0,0 +1,164 @@
+package httperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+)
+
+var testQuotaExceeded = errnie.RegisterKind("httperr_test_quota_exceeded", errnie.KindInfo{
+	HTTPStatus: http.StatusPaymentRequired,
+})
+
+/*
+TestStatusFor verifies Kind to HTTP status mapping through wrapped chains.
+*/
+func TestStatusFor(t *testing.T) {
+	Convey("Given errors of each mapped Kind", t, func() {
+		cases := map[errnie.Kind]int{
+			errnie.Validation:           http.StatusBadRequest,
+			errnie.BadRequest:           http.StatusBadRequest,
+			errnie.Unauthorized:         http.StatusUnauthorized,
+			errnie.Forbidden:            http.StatusForbidden,
+			errnie.NotFound:             http.StatusNotFound,
+			errnie.MethodNotAllowed:     http.StatusMethodNotAllowed,
+			errnie.NotAcceptable:        http.StatusNotAcceptable,
+			errnie.Conflict:             http.StatusConflict,
+			errnie.PreconditionFailed:   http.StatusPreconditionFailed,
+			errnie.UnsupportedMedia:     http.StatusUnsupportedMediaType,
+			errnie.ExpectationFailed:    http.StatusExpectationFailed,
+			errnie.UnprocessableContent: http.StatusUnprocessableEntity,
+			errnie.TooManyRequests:      http.StatusTooManyRequests,
+			errnie.Canceled:             StatusClientClosedRequest,
+			errnie.Timeout:              http.StatusGatewayTimeout,
+			errnie.DeadlineExceeded:     http.StatusGatewayTimeout,
+			errnie.Internal:             http.StatusInternalServerError,
+			errnie.NotImplemented:       http.StatusNotImplemented,
+			errnie.BadGateway:           http.StatusBadGateway,
+			errnie.ServiceUnavailable:   http.StatusServiceUnavailable,
+			errnie.Unknown:              http.StatusInternalServerError,
+			errnie.IO:                   http.StatusInternalServerError,
+		}
+
+		Convey("When StatusFor is called on wrapped errors", func() {
+			Convey("Then it should return the expected status", func() {
+				for kind, status := range cases {
+					err := fmt.Errorf("wrap: %w", errnie.Err(kind, "failed", nil))
+					So(StatusFor(err), ShouldEqual, status)
+				}
+			})
+		})
+	})
+
+	Convey("Given a nil error", t, func() {
+		Convey("When StatusFor is called", func() {
+			Convey("Then it should return 200", func() {
+				So(StatusFor(nil), ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a plain error", t, func() {
+		Convey("When StatusFor is called", func() {
+			Convey("Then it should default to 500", func() {
+				So(StatusFor(errors.New("plain")), ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+	})
+
+	Convey("Given an application Kind from the registry", t, func() {
+		err := errnie.Err(testQuotaExceeded, "quota exceeded", nil)
+
+		Convey("When StatusFor and FromStatus are called", func() {
+			Convey("Then both directions should use the registered status", func() {
+				So(StatusFor(err), ShouldEqual, http.StatusPaymentRequired)
+				So(errnie.IsKind(FromStatus(http.StatusPaymentRequired, nil), testQuotaExceeded), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a combined error", t, func() {
+		err := errnie.Combine(errors.New("plain"), errnie.Err(errnie.NotFound, "missing", nil))
+
+		Convey("When StatusFor is called", func() {
+			Convey("Then it should map the first ErrnieError in the chain", func() {
+				So(StatusFor(err), ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
+
+/*
+TestFromStatus verifies upstream status codes become classified ErrnieErrors.
+*/
+func TestFromStatus(t *testing.T) {
+	Convey("Given a 2xx status", t, func() {
+		Convey("When FromStatus is called", func() {
+			Convey("Then it should return nil", func() {
+				So(FromStatus(http.StatusNoContent, nil), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a 404 with a body", t, func() {
+		Convey("When FromStatus is called", func() {
+			err := FromStatus(http.StatusNotFound, []byte("  user 7 missing\n"))
+			target, ok := errnie.AsErrnie(err)
+
+			Convey("Then it should produce a NotFound error with the body as message", func() {
+				So(ok, ShouldBeTrue)
+				So(errnie.IsNotFound(err), ShouldBeTrue)
+				So(target.Message, ShouldEqual, "user 7 missing")
+				So(target.Fields(), ShouldResemble, []any{"status", http.StatusNotFound})
+			})
+		})
+	})
+
+	Convey("Given statuses without a dedicated Kind", t, func() {
+		Convey("When FromStatus is called", func() {
+			Convey("Then it should fall back by status class", func() {
+				So(errnie.IsBadRequest(FromStatus(http.StatusTeapot, nil)), ShouldBeTrue)
+				So(errnie.IsInternal(FromStatus(http.StatusInsufficientStorage, nil)), ShouldBeTrue)
+				So(errnie.IsUnknown(FromStatus(http.StatusFound, nil)), ShouldBeTrue)
+				So(errnie.IsTimeout(FromStatus(http.StatusRequestTimeout, nil)), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given an empty body", t, func() {
+		Convey("When FromStatus is called", func() {
+			err := FromStatus(http.StatusServiceUnavailable, nil)
+
+			Convey("Then the message should be the status text", func() {
+				So(err.Error(), ShouldStartWith, "Service Unavailable")
+			})
+		})
+	})
+
+	Convey("Given every mapped status", t, func() {
+		Convey("When the Kind is mapped back with StatusFor", func() {
+			Convey("Then the status should round-trip", func() {
+				for _, status := range []int{400, 401, 403, 404, 405, 406, 409, 412, 415, 417, 422, 429, 499, 500, 501, 502, 503, 504} {
+					So(StatusFor(FromStatus(status, nil)), ShouldEqual, status)
+				}
+			})
+		})
+	})
+}
+
+var benchmarkStatusSink int
+
+/*
+BenchmarkStatusFor measures status mapping for a wrapped ErrnieError.
+*/
+func BenchmarkStatusFor(b *testing.B) {
+	err := fmt.Errorf("wrap: %w", errnie.Err(errnie.NotFound, "missing", nil))
+
+	for b.Loop() {
+		benchmarkStatusSink = StatusFor(err)
+	}
+}
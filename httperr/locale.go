@@ -0,0 +1,97 @@
+package httperr
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+AcceptLanguage parses an Accept-Language header into language tags ordered by
+preference. Tags with q=0 and the "*" wildcard are dropped; tags with equal
+weight keep their header order.
+*/
+func AcceptLanguage(header string) []string {
+	type weighted struct {
+		tag    string
+		weight float64
+	}
+
+	var tags []weighted
+
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+
+		for param := range strings.SplitSeq(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				weight = parsed
+			}
+		}
+
+		if weight > 0 {
+			tags = append(tags, weighted{tag: tag, weight: weight})
+		}
+	}
+
+	slices.SortStableFunc(tags, func(left, right weighted) int {
+		switch {
+		case left.weight > right.weight:
+			return -1
+		case left.weight < right.weight:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	locales := make([]string, len(tags))
+
+	for index, tag := range tags {
+		locales[index] = tag.tag
+	}
+
+	return locales
+}
+
+/*
+RequestLocale returns the most preferred Accept-Language tag of r that the
+active errnie catalog supports, or "" when none is.
+*/
+func RequestLocale(r *http.Request) string {
+	for _, locale := range AcceptLanguage(r.Header.Get("Accept-Language")) {
+		if errnie.SupportsLocale(locale) {
+			return locale
+		}
+	}
+
+	return ""
+}
+
+/*
+LocaleMiddleware stores the RequestLocale in the request context, where
+errnie.Localize and Problem.Localize pick it up.
+*/
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if locale := RequestLocale(r); locale != "" {
+			r = r.WithContext(errnie.WithLocale(r.Context(), locale))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,79 @@
+package httperr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+TestAcceptLanguage verifies Accept-Language parsing and ordering.
+*/
+func TestAcceptLanguage(t *testing.T) {
+	Convey("Given an Accept-Language header with weights", t, func() {
+		header := "fr-CH, fr;q=0.9, en;q=0.8, de;q=0.9, *;q=0.5, it;q=0"
+
+		Convey("When it is parsed", func() {
+			locales := AcceptLanguage(header)
+
+			Convey("Then tags should be ordered by weight without wildcards or q=0", func() {
+				So(locales, ShouldResemble, []string{"fr-CH", "fr", "de", "en"})
+			})
+		})
+	})
+
+	Convey("Given an empty header", t, func() {
+		Convey("When it is parsed", func() {
+			Convey("Then no tags should be returned", func() {
+				So(AcceptLanguage(""), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+/*
+TestLocaleMiddleware verifies request locale negotiation against the catalog.
+*/
+func TestLocaleMiddleware(t *testing.T) {
+	Convey("Given a catalog with English and German messages", t, func() {
+		errnie.SetCatalog(errnie.NewMapCatalog(map[string]map[string]string{
+			"en": {"user.not_found": "user {id} not found"},
+			"de": {"user.not_found": "Benutzer {id} wurde nicht gefunden"},
+		}), "en")
+		defer errnie.SetCatalog(nil, "")
+
+		err := errnie.ErrKey(errnie.NotFound, "user.not_found", nil, "id", 7)
+
+		Convey("When a request prefers an unsupported locale over German", func() {
+			request := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+			request.Header.Set("Accept-Language", "fr;q=1, de-AT;q=0.7, en;q=0.5")
+
+			var localized string
+			var detail string
+
+			LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				localized = errnie.Localize(r.Context(), err)
+				detail = NewProblem(err).Localize(r.Context(), err).Detail
+			})).ServeHTTP(httptest.NewRecorder(), request)
+
+			Convey("Then the first supported locale should be used", func() {
+				So(RequestLocale(request), ShouldEqual, "de-AT")
+				So(localized, ShouldEqual, "Benutzer 7 wurde nicht gefunden")
+				So(detail, ShouldEqual, "Benutzer 7 wurde nicht gefunden")
+			})
+		})
+
+		Convey("When a problem is localized for an error without a key", func() {
+			plain := errnie.Err(errnie.Conflict, "duplicate", nil)
+			problem := NewProblem(plain).Localize(errnie.WithLocale(context.Background(), "de"), plain)
+
+			Convey("Then Detail should keep the message", func() {
+				So(problem.Detail, ShouldEqual, "duplicate")
+			})
+		})
+	})
+}
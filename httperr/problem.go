@@ -0,0 +1,290 @@
+package httperr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+ProblemContentType is the media type of RFC 7807 problem documents.
+*/
+const ProblemContentType = "application/problem+json"
+
+/*
+ProblemTypePrefix prefixes the Kind name to form a problem "type" URI, for
+example "urn:errnie:kind:not_found".
+*/
+const ProblemTypePrefix = "urn:errnie:kind:"
+
+/*
+Problem is an RFC 7807 problem details document. Extensions holds any members
+beyond the five standard ones and is flattened into the top-level JSON object.
+*/
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+/*
+problemMembers lists the standard members that extensions may not override.
+*/
+var problemMembers = []string{"type", "title", "status", "detail", "instance", "code"}
+
+/*
+NewProblem builds a problem document from err. type and title come from the
+Kind, detail from Message, instance from Op, the first errnie.Code in the chain
+becomes the "code" member, and string-keyed With fields become extension
+members after redaction (see errnie.RedactionPolicy). The cause chain is never
+included; errors without an ErrnieError render as an opaque 500.
+*/
+func NewProblem(err error) *Problem {
+	problem := &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(http.StatusInternalServerError),
+		Status:     http.StatusInternalServerError,
+		Extensions: map[string]any{},
+	}
+
+	target, ok := errnie.AsErrnie(err)
+	if !ok {
+		return problem
+	}
+
+	name := errnie.KindName(target.Kind)
+
+	problem.Type = ProblemTypePrefix + name
+	problem.Title = kindTitle(name)
+	problem.Status = statusForKind(target.Kind)
+	problem.Detail = target.Message
+	problem.Instance = target.Op
+
+	if target.MessageKey != "" {
+		problem.Detail = errnie.Localize(context.Background(), target)
+	}
+
+	if code := errnie.CodeOf(err); code != "" {
+		problem.Extensions["code"] = string(code)
+	}
+
+	fields := target.Fields()
+
+	for index := 0; index+1 < len(fields); index += 2 {
+		key, ok := fields[index].(string)
+		if !ok || slices.Contains(problemMembers, key) {
+			continue
+		}
+
+		problem.Extensions[key] = errnie.Redact(key, fields[index+1])
+	}
+
+	return problem
+}
+
+/*
+Localize replaces Detail with err's message in the locale carried by ctx when
+err was built with errnie.ErrKey. See LocaleMiddleware.
+*/
+func (problem *Problem) Localize(ctx context.Context, err error) *Problem {
+	if target, ok := errnie.AsErrnie(err); ok && target.MessageKey != "" {
+		problem.Detail = errnie.Localize(ctx, target)
+	}
+
+	return problem
+}
+
+/*
+WithCause opts in to exposing err's full message under the "cause" extension.
+Only use it for trusted clients; the default document never leaks causes.
+*/
+func (problem *Problem) WithCause(err error) *Problem {
+	if err == nil {
+		return problem
+	}
+
+	if problem.Extensions == nil {
+		problem.Extensions = map[string]any{}
+	}
+
+	problem.Extensions["cause"] = err.Error()
+
+	return problem
+}
+
+/*
+Err converts the problem back into an ErrnieError. The Kind is recovered from
+an errnie type URI, falling back to the status code; detail (or title) becomes
+the message, instance the Op, a string "code" extension the Code, and the
+remaining extensions are attached in key order.
+*/
+func (problem *Problem) Err() *errnie.ErrnieError {
+	kind, ok := kindFromType(problem.Type)
+	if !ok {
+		kind = kindForStatus(problem.Status)
+	}
+
+	message := problem.Detail
+
+	if message == "" {
+		message = problem.Title
+	}
+
+	err := errnie.Err(kind, message, nil).Operation(problem.Instance)
+
+	if problem.Status != 0 {
+		err.With("status", problem.Status)
+	}
+
+	keys := make([]string, 0, len(problem.Extensions))
+
+	for key, value := range problem.Extensions {
+		if code, ok := value.(string); ok && key == "code" {
+			err.Code = errnie.Code(code)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		err.With(key, problem.Extensions[key])
+	}
+
+	return err
+}
+
+/*
+MarshalJSON flattens Extensions next to the standard members. Empty standard
+members are omitted, as RFC 7807 allows.
+*/
+func (problem Problem) MarshalJSON() ([]byte, error) {
+	document := make(map[string]any, len(problem.Extensions)+len(problemMembers))
+
+	for key, value := range problem.Extensions {
+		document[key] = value
+	}
+
+	setMember(document, "type", problem.Type)
+	setMember(document, "title", problem.Title)
+	setMember(document, "detail", problem.Detail)
+	setMember(document, "instance", problem.Instance)
+
+	if problem.Status != 0 {
+		document["status"] = problem.Status
+	}
+
+	return json.Marshal(document)
+}
+
+/*
+UnmarshalJSON reads the standard members and collects every other member into
+Extensions.
+*/
+func (problem *Problem) UnmarshalJSON(data []byte) error {
+	var document map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	*problem = Problem{Extensions: map[string]any{}}
+
+	targets := map[string]any{
+		"type":     &problem.Type,
+		"title":    &problem.Title,
+		"status":   &problem.Status,
+		"detail":   &problem.Detail,
+		"instance": &problem.Instance,
+	}
+
+	for key, raw := range document {
+		if target, ok := targets[key]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var value any
+
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+
+		problem.Extensions[key] = value
+	}
+
+	return nil
+}
+
+/*
+WriteProblem renders err as an application/problem+json response with the
+status code from its Kind.
+*/
+func WriteProblem(writer http.ResponseWriter, err error) error {
+	problem := NewProblem(err)
+
+	writer.Header().Set("Content-Type", ProblemContentType)
+	writer.WriteHeader(problem.Status)
+
+	return json.NewEncoder(writer).Encode(problem)
+}
+
+/*
+ParseProblem decodes a problem document, typically an upstream response body,
+into an ErrnieError so callers can classify it with IsNotFound and friends.
+*/
+func ParseProblem(data []byte) (*errnie.ErrnieError, error) {
+	var problem Problem
+
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return nil, errnie.Err(errnie.Validation, "invalid problem document", err)
+	}
+
+	return problem.Err(), nil
+}
+
+/*
+setMember stores a non-empty string member in document.
+*/
+func setMember(document map[string]any, key, value string) {
+	if value != "" {
+		document[key] = value
+	}
+}
+
+/*
+kindFromType recovers a registered Kind from an errnie problem type URI.
+*/
+func kindFromType(problemType string) (errnie.Kind, bool) {
+	name, ok := strings.CutPrefix(problemType, ProblemTypePrefix)
+	if !ok {
+		return nil, false
+	}
+
+	return errnie.ParseKind(name)
+}
+
+/*
+kindTitle turns a snake_case Kind name into a title such as "Not Found".
+*/
+func kindTitle(name string) string {
+	words := strings.Fields(strings.ReplaceAll(name, "_", " "))
+
+	for index, word := range words {
+		words[index] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	return strings.Join(words, " ")
+}
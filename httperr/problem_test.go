@@ -0,0 +1,222 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+)
+
+var testEmailTaken = errnie.RegisterCode("HTTPERR_TEST_EMAIL_TAKEN", errnie.CodeInfo{
+	Kind:    errnie.Conflict,
+	Message: "email address is already registered",
+})
+
+/*
+TestNewProblem verifies problem documents built from ErrnieErrors.
+*/
+func TestNewProblem(t *testing.T) {
+	Convey("Given an ErrnieError with operation, fields, and a cause", t, func() {
+		err := errnie.Err(
+			errnie.NotFound, "user 7 does not exist", errors.New("sql: no rows"),
+		).Operation("/users/7").With("user_id", 7, "status", 418)
+
+		Convey("When NewProblem is called", func() {
+			problem := NewProblem(err)
+
+			Convey("Then the members should come from Kind, Message, Op, and fields", func() {
+				So(problem.Type, ShouldEqual, "urn:errnie:kind:not_found")
+				So(problem.Title, ShouldEqual, "Not Found")
+				So(problem.Status, ShouldEqual, http.StatusNotFound)
+				So(problem.Detail, ShouldEqual, "user 7 does not exist")
+				So(problem.Instance, ShouldEqual, "/users/7")
+				So(problem.Extensions, ShouldResemble, map[string]any{"user_id": 7})
+			})
+
+			Convey("Then the cause should not leak into the JSON body", func() {
+				body, marshalErr := json.Marshal(problem)
+				So(marshalErr, ShouldBeNil)
+				So(string(body), ShouldNotContainSubstring, "sql: no rows")
+			})
+		})
+
+		Convey("When WithCause is used", func() {
+			problem := NewProblem(err).WithCause(err.Cause)
+
+			Convey("Then the cause should be exposed as an extension", func() {
+				So(problem.Extensions["cause"], ShouldEqual, "sql: no rows")
+			})
+		})
+	})
+
+	Convey("Given an ErrnieError with a registered code behind a wrapper", t, func() {
+		code := testEmailTaken
+		err := errnie.Err(errnie.Conflict, "signup failed", errnie.ErrCode(code, nil))
+
+		Convey("When NewProblem is called and the body is parsed back", func() {
+			problem := NewProblem(err)
+			body, _ := json.Marshal(problem)
+			parsed, parseErr := ParseProblem(body)
+
+			Convey("Then the code should be a member that maps back to Code", func() {
+				So(problem.Extensions["code"], ShouldEqual, "HTTPERR_TEST_EMAIL_TAKEN")
+				So(parseErr, ShouldBeNil)
+				So(parsed.Code, ShouldEqual, code)
+				So(errnie.IsCode(parsed, code), ShouldBeTrue)
+				So(parsed.Fields(), ShouldResemble, []any{"status", http.StatusConflict})
+			})
+		})
+	})
+
+	Convey("Given a plain error", t, func() {
+		Convey("When NewProblem is called", func() {
+			problem := NewProblem(errors.New("secret internals"))
+
+			Convey("Then it should render an opaque 500", func() {
+				So(problem.Type, ShouldEqual, "about:blank")
+				So(problem.Status, ShouldEqual, http.StatusInternalServerError)
+				So(problem.Detail, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an ErrnieError with a Secret field", t, func() {
+		err := errnie.Err(errnie.Unauthorized, "token rejected", nil).With(
+			"token", errnie.Secret("t0k3n"),
+			"realm", "api",
+		)
+
+		Convey("When NewProblem is called", func() {
+			problem := NewProblem(err)
+
+			Convey("Then the secret extension should be masked", func() {
+				So(problem.Extensions["token"], ShouldEqual, errnie.DefaultRedactionMask)
+				So(problem.Extensions["realm"], ShouldEqual, "api")
+			})
+		})
+	})
+}
+
+/*
+TestProblemJSON verifies extension flattening and collection.
+*/
+func TestProblemJSON(t *testing.T) {
+	Convey("Given a problem with extensions", t, func() {
+		problem := &Problem{
+			Type:       ProblemTypePrefix + "conflict",
+			Title:      "Conflict",
+			Status:     http.StatusConflict,
+			Extensions: map[string]any{"field": "email"},
+		}
+
+		Convey("When it is marshaled and unmarshaled", func() {
+			body, err := json.Marshal(problem)
+			So(err, ShouldBeNil)
+
+			var decoded Problem
+			So(json.Unmarshal(body, &decoded), ShouldBeNil)
+
+			Convey("Then the extensions should be top-level members that round-trip", func() {
+				So(string(body), ShouldContainSubstring, `"field":"email"`)
+				So(string(body), ShouldNotContainSubstring, "detail")
+				So(decoded.Type, ShouldEqual, problem.Type)
+				So(decoded.Status, ShouldEqual, http.StatusConflict)
+				So(decoded.Extensions, ShouldResemble, map[string]any{"field": "email"})
+			})
+		})
+
+		Convey("When it is marshaled as a value", func() {
+			byValue, err := json.Marshal(*problem)
+			So(err, ShouldBeNil)
+
+			byPointer, _ := json.Marshal(problem)
+
+			Convey("Then it should encode the same RFC 7807 document", func() {
+				So(string(byValue), ShouldEqual, string(byPointer))
+				So(string(byValue), ShouldNotContainSubstring, "Extensions")
+			})
+		})
+	})
+}
+
+/*
+TestWriteProblem verifies the HTTP response for a problem document.
+*/
+func TestWriteProblem(t *testing.T) {
+	Convey("Given a validation error", t, func() {
+		err := errnie.Err(errnie.Validation, "email is invalid", nil).With("field", "email")
+
+		Convey("When WriteProblem renders it", func() {
+			recorder := httptest.NewRecorder()
+			So(WriteProblem(recorder, err), ShouldBeNil)
+
+			Convey("Then the response should be a 400 problem+json body", func() {
+				So(recorder.Code, ShouldEqual, http.StatusBadRequest)
+				So(recorder.Header().Get("Content-Type"), ShouldEqual, ProblemContentType)
+				So(recorder.Body.String(), ShouldContainSubstring, `"type":"urn:errnie:kind:validation"`)
+			})
+		})
+	})
+}
+
+/*
+TestParseProblem verifies problem documents decode into classified errors.
+*/
+func TestParseProblem(t *testing.T) {
+	Convey("Given an errnie problem document", t, func() {
+		body := []byte(`{"type":"urn:errnie:kind:not_found","title":"Not Found","status":404,"detail":"user missing","instance":"user.load","user_id":7}`)
+
+		Convey("When ParseProblem is called", func() {
+			err, parseErr := ParseProblem(body)
+
+			Convey("Then the ErrnieError should carry the original classification", func() {
+				So(parseErr, ShouldBeNil)
+				So(errnie.IsNotFound(err), ShouldBeTrue)
+				So(err.Message, ShouldEqual, "user missing")
+				So(err.Op, ShouldEqual, "user.load")
+				So(err.Fields(), ShouldResemble, []any{"status", 404, "user_id", float64(7)})
+			})
+		})
+	})
+
+	Convey("Given a foreign problem document", t, func() {
+		body := []byte(`{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403}`)
+
+		Convey("When ParseProblem is called", func() {
+			err, parseErr := ParseProblem(body)
+
+			Convey("Then the Kind should come from the status", func() {
+				So(parseErr, ShouldBeNil)
+				So(errnie.IsForbidden(err), ShouldBeTrue)
+				So(err.Message, ShouldEqual, "You do not have enough credit.")
+			})
+		})
+	})
+
+	Convey("Given a context kind round-tripped through a problem", t, func() {
+		problem := NewProblem(errnie.Err(errnie.DeadlineExceeded, "too slow", nil))
+
+		Convey("When the problem is converted back", func() {
+			err := problem.Err()
+
+			Convey("Then the Kind should be recovered from the type", func() {
+				So(problem.Type, ShouldEqual, "urn:errnie:kind:deadline_exceeded")
+				So(errnie.IsDeadlineExceeded(err), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given malformed JSON", t, func() {
+		Convey("When ParseProblem is called", func() {
+			_, err := ParseProblem([]byte("{"))
+
+			Convey("Then it should return a validation error", func() {
+				So(errnie.IsValidation(err), ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,240 @@
+package errnie
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+/*
+errorJSON is one node of the wire encoding. Exactly one shape is used per node:
+an ErrnieError (kind set), a join (joined set), a Kind sentinel such as
+context.Canceled (sentinel set), or any other error (text set). Single-cause
+wrapping is preserved through cause.
+*/
+type errorJSON struct {
+	Kind        string       `json:"kind,omitempty"`
+	Op          string       `json:"op,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	MessageKey  string       `json:"message_key,omitempty"`
+	MessageArgs []any        `json:"message_args,omitempty"`
+	Code        string       `json:"code,omitempty"`
+	Timestamp   int64        `json:"timestamp,omitempty"`
+	Fields      []any        `json:"fields,omitempty"`
+	Sentinel    string       `json:"sentinel,omitempty"`
+	Text        string       `json:"text,omitempty"`
+	Joined      []*errorJSON `json:"joined,omitempty"`
+	Cause       *errorJSON   `json:"cause,omitempty"`
+}
+
+/*
+decodedError stands in for a non-errnie error after decoding. It keeps the
+original text and the decoded cause so errors.Is still reaches sentinels
+further down the chain.
+*/
+type decodedError struct {
+	text  string
+	cause error
+}
+
+func (err *decodedError) Error() string {
+	return err.text
+}
+
+func (err *decodedError) Unwrap() error {
+	return err.cause
+}
+
+/*
+MarshalJSON encodes the error with Kind by name, its fields, and the full
+cause chain, including Combine and errors.Join branches. Field values are
+redacted first (see RedactionPolicy); values that are errors are encoded as
+their message and all other values must be JSON-encodable.
+*/
+func (err *ErrnieError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeErrnie(err))
+}
+
+/*
+UnmarshalJSON decodes an ErrnieError written by MarshalJSON. Kind names that
+are not registered (see RegisterKind) decode as Unknown. Numeric field values
+decode as float64.
+*/
+func (err *ErrnieError) UnmarshalJSON(data []byte) error {
+	var node errorJSON
+
+	if decodeErr := json.Unmarshal(data, &node); decodeErr != nil {
+		return decodeErr
+	}
+
+	if node.Kind == "" {
+		return Err(Validation, "not an encoded ErrnieError", nil)
+	}
+
+	decoded := decodeErrnie(&node)
+
+	err.Kind = decoded.Kind
+	err.Op = decoded.Op
+	err.Message = decoded.Message
+	err.MessageKey = decoded.MessageKey
+	err.MessageArgs = decoded.MessageArgs
+	err.Code = decoded.Code
+	err.Cause = decoded.Cause
+	err.Timestamp = decoded.Timestamp
+	err.fields = decoded.fields
+	err.stack = nil
+	err.rendered.Store(nil)
+
+	return nil
+}
+
+/*
+MarshalError encodes any error chain in the ErrnieError wire format, so joined
+errors without an ErrnieError at the root can cross process boundaries too.
+*/
+func MarshalError(err error) ([]byte, error) {
+	return json.Marshal(encodeError(err))
+}
+
+/*
+UnmarshalError decodes an error chain written by MarshalError or
+ErrnieError.MarshalJSON. The decoded error supports IsKind, and errors.Is
+against any registered Kind wherever it appeared in the original chain.
+*/
+func UnmarshalError(data []byte) (error, error) {
+	var node *errorJSON
+
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+
+	return decodeError(node), nil
+}
+
+/*
+encodeError converts one error in a chain to its wire node.
+*/
+func encodeError(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+
+	if target, ok := err.(*ErrnieError); ok {
+		return encodeErrnie(target)
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		node := &errorJSON{}
+
+		for _, child := range joined.Unwrap() {
+			if child != nil {
+				node.Joined = append(node.Joined, encodeError(child))
+			}
+		}
+
+		return node
+	}
+
+	if info, ok := LookupKind(err); ok {
+		return &errorJSON{Sentinel: info.Name}
+	}
+
+	return &errorJSON{
+		Text:  err.Error(),
+		Cause: encodeError(errors.Unwrap(err)),
+	}
+}
+
+/*
+encodeErrnie converts an ErrnieError to its wire node.
+*/
+func encodeErrnie(err *ErrnieError) *errorJSON {
+	if err == nil {
+		return nil
+	}
+
+	return &errorJSON{
+		Kind:        KindName(err.Kind),
+		Op:          err.Op,
+		Message:     err.message(),
+		MessageKey:  err.MessageKey,
+		MessageArgs: encodeFields(err.MessageArgs),
+		Code:        string(err.Code),
+		Timestamp:   err.Timestamp,
+		Fields:      encodeFields(err.fields),
+		Cause:       encodeError(err.Cause),
+	}
+}
+
+/*
+encodeFields redacts alternating key/value pairs and replaces error values
+with their message.
+*/
+func encodeFields(fields []any) []any {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	fields = redactFields(fields)
+	encoded := make([]any, len(fields))
+
+	for index, value := range fields {
+		if valueErr, ok := value.(error); ok {
+			value = valueErr.Error()
+		}
+
+		encoded[index] = value
+	}
+
+	return encoded
+}
+
+/*
+decodeError converts a wire node back into an error.
+*/
+func decodeError(node *errorJSON) error {
+	switch {
+	case node == nil:
+		return nil
+	case node.Kind != "":
+		return decodeErrnie(node)
+	case node.Joined != nil:
+		children := make([]error, 0, len(node.Joined))
+
+		for _, child := range node.Joined {
+			children = append(children, decodeError(child))
+		}
+
+		return Combine(children...)
+	case node.Sentinel != "":
+		if kind, ok := ParseKind(node.Sentinel); ok {
+			return kind
+		}
+
+		return &decodedError{text: node.Sentinel}
+	default:
+		return &decodedError{text: node.Text, cause: decodeError(node.Cause)}
+	}
+}
+
+/*
+decodeErrnie converts an ErrnieError wire node back into an ErrnieError.
+*/
+func decodeErrnie(node *errorJSON) *ErrnieError {
+	kind, ok := ParseKind(node.Kind)
+	if !ok {
+		kind = Unknown
+	}
+
+	err := &ErrnieError{
+		Kind:        kind,
+		Op:          node.Op,
+		Message:     node.Message,
+		MessageKey:  node.MessageKey,
+		MessageArgs: node.MessageArgs,
+		Code:        Code(node.Code),
+		Cause:       decodeError(node.Cause),
+		Timestamp:   node.Timestamp,
+	}
+
+	return err.With(node.Fields...)
+}
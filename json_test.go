@@ -0,0 +1,131 @@
+package errnie
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+TestErrnieErrorJSON verifies the ErrnieError wire format round-trip.
+*/
+func TestErrnieErrorJSON(t *testing.T) {
+	Convey("Given an ErrnieError with fields and a wrapped context cause", t, func() {
+		original := Err(Timeout, "upstream timed out", fmt.Errorf("dial: %w", context.DeadlineExceeded)).
+			Operation("billing.charge").
+			With("attempt", 3, "reason", errors.New("slow")).
+			WithTimestamp(1700000000)
+
+		Convey("When it is marshaled", func() {
+			data, err := json.Marshal(original)
+
+			Convey("Then Kind should be encoded by name", func() {
+				So(err, ShouldBeNil)
+				So(string(data), ShouldContainSubstring, `"kind":"timeout"`)
+				So(string(data), ShouldContainSubstring, `"sentinel":"deadline_exceeded"`)
+				So(string(data), ShouldContainSubstring, `"fields":["attempt",3,"reason","slow"]`)
+			})
+
+			Convey("When it is unmarshaled again", func() {
+				var decoded ErrnieError
+				So(json.Unmarshal(data, &decoded), ShouldBeNil)
+
+				Convey("Then kinds, fields, and sentinels should survive", func() {
+					So(IsTimeout(&decoded), ShouldBeTrue)
+					So(decoded.Op, ShouldEqual, "billing.charge")
+					So(decoded.Message, ShouldEqual, "upstream timed out")
+					So(decoded.Timestamp, ShouldEqual, 1700000000)
+					So(decoded.Fields(), ShouldResemble, []any{"attempt", float64(3), "reason", "slow"})
+					So(errors.Is(&decoded, context.DeadlineExceeded), ShouldBeTrue)
+					So(errors.Is(&decoded, DeadlineExceeded), ShouldBeTrue)
+					So(IsContext(&decoded), ShouldBeTrue)
+					So(decoded.Error(), ShouldEqual, original.Error())
+				})
+			})
+		})
+	})
+
+	Convey("Given JSON that is not an encoded ErrnieError", t, func() {
+		Convey("When it is unmarshaled", func() {
+			var decoded ErrnieError
+			err := json.Unmarshal([]byte(`{"text":"plain"}`), &decoded)
+
+			Convey("Then it should return a validation error", func() {
+				So(IsValidation(err), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given an unregistered kind name", t, func() {
+		Convey("When it is unmarshaled", func() {
+			var decoded ErrnieError
+			err := json.Unmarshal([]byte(`{"kind":"mystery","message":"odd"}`), &decoded)
+
+			Convey("Then it should decode as Unknown", func() {
+				So(err, ShouldBeNil)
+				So(IsUnknown(&decoded), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+TestMarshalError verifies encoding of arbitrary chains including joins.
+*/
+func TestMarshalError(t *testing.T) {
+	Convey("Given a Combine of three branches", t, func() {
+		original := Combine(
+			Err(NotFound, "user missing", nil),
+			fmt.Errorf("flush: %w", io.EOF),
+			Err(IO, "close failed", errors.New("bad fd")),
+		)
+
+		Convey("When it is marshaled and unmarshaled", func() {
+			data, err := MarshalError(original)
+			So(err, ShouldBeNil)
+
+			decoded, err := UnmarshalError(data)
+			So(err, ShouldBeNil)
+
+			Convey("Then every branch should remain classifiable", func() {
+				So(IsNotFound(decoded), ShouldBeTrue)
+				So(IsIO(decoded), ShouldBeTrue)
+				So(errors.Is(decoded, io.EOF), ShouldBeTrue)
+				So(decoded.Error(), ShouldEqual, original.Error())
+			})
+		})
+	})
+
+	Convey("Given a nil error", t, func() {
+		Convey("When it is marshaled and unmarshaled", func() {
+			data, err := MarshalError(nil)
+			So(err, ShouldBeNil)
+
+			decoded, err := UnmarshalError(data)
+
+			Convey("Then it should decode to nil", func() {
+				So(string(data), ShouldEqual, "null")
+				So(err, ShouldBeNil)
+				So(decoded, ShouldBeNil)
+			})
+		})
+	})
+}
+
+var benchmarkJSONSink []byte
+
+/*
+BenchmarkErrnieErrorMarshalJSON measures encoding of a wrapped ErrnieError.
+*/
+func BenchmarkErrnieErrorMarshalJSON(b *testing.B) {
+	err := Err(IO, "read failed", benchmarkStaticCause).Operation("file.read").With("path", "/tmp/x")
+
+	for b.Loop() {
+		benchmarkJSONSink, _ = json.Marshal(err)
+	}
+}
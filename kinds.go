@@ -0,0 +1,231 @@
+package errnie
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+KindInfo describes a Kind for serializers, transport mappings, retry policies,
+and metrics. It is the single source of truth those layers read instead of
+keeping their own switches.
+*/
+type KindInfo struct {
+	// Kind is the classification value. RegisterKind creates one from the
+	// name when it is nil.
+	Kind Kind
+	// Name is the stable identifier used on the wire. RegisterKind sets it.
+	Name string
+	// HTTPStatus is the status code used at HTTP boundaries; 0 means 500.
+	HTTPStatus int
+	// GRPCCode is the google.golang.org/grpc/codes value for gRPC boundaries.
+	GRPCCode uint32
+	// Retryable marks transient failures that are safe to retry.
+	Retryable bool
+	// Severity is the log level name ("info", "warn", "error") for the Kind.
+	Severity string
+	// Description is a short human-readable explanation of the Kind.
+	Description string
+}
+
+/*
+kindRegistry is an immutable snapshot of registered Kinds in registration
+order, with an index from Kind to position so lookups do not scan. Writers
+copy it; readers load it with a single atomic read.
+*/
+type kindRegistry struct {
+	infos []KindInfo
+	index map[Kind]int
+}
+
+var (
+	kindRegistrySnapshot atomic.Pointer[kindRegistry]
+	kindRegistryMutex    sync.Mutex
+)
+
+func init() {
+	for _, info := range []KindInfo{
+		{Kind: Internal, Name: "internal", HTTPStatus: http.StatusInternalServerError, GRPCCode: 13, Severity: "error", Description: "unexpected failure inside the service"},
+		{Kind: BadRequest, Name: "bad_request", HTTPStatus: http.StatusBadRequest, GRPCCode: 3, Severity: "warn", Description: "malformed request"},
+		{Kind: Validation, Name: "validation", HTTPStatus: http.StatusBadRequest, GRPCCode: 3, Severity: "warn", Description: "input failed validation"},
+		{Kind: Unauthorized, Name: "unauthorized", HTTPStatus: http.StatusUnauthorized, GRPCCode: 16, Severity: "warn", Description: "missing or invalid credentials"},
+		{Kind: Forbidden, Name: "forbidden", HTTPStatus: http.StatusForbidden, GRPCCode: 7, Severity: "warn", Description: "caller lacks permission"},
+		{Kind: NotFound, Name: "not_found", HTTPStatus: http.StatusNotFound, GRPCCode: 5, Severity: "info", Description: "resource does not exist"},
+		{Kind: MethodNotAllowed, Name: "method_not_allowed", HTTPStatus: http.StatusMethodNotAllowed, GRPCCode: 12, Severity: "warn", Description: "operation not supported on the resource"},
+		{Kind: NotAcceptable, Name: "not_acceptable", HTTPStatus: http.StatusNotAcceptable, GRPCCode: 3, Severity: "warn", Description: "no acceptable representation"},
+		{Kind: Timeout, Name: "timeout", HTTPStatus: http.StatusGatewayTimeout, GRPCCode: 4, Retryable: true, Severity: "error", Description: "operation timed out"},
+		{Kind: DeadlineExceeded, Name: "deadline_exceeded", HTTPStatus: http.StatusGatewayTimeout, GRPCCode: 4, Severity: "warn", Description: "context deadline exceeded"},
+		{Kind: Canceled, Name: "canceled", HTTPStatus: 499, GRPCCode: 1, Severity: "info", Description: "context canceled by the caller"},
+		{Kind: Conflict, Name: "conflict", HTTPStatus: http.StatusConflict, GRPCCode: 6, Severity: "warn", Description: "state conflict such as a duplicate"},
+		{Kind: PreconditionFailed, Name: "precondition_failed", HTTPStatus: http.StatusPreconditionFailed, GRPCCode: 9, Severity: "warn", Description: "precondition on the resource failed"},
+		{Kind: UnsupportedMedia, Name: "unsupported_media_type", HTTPStatus: http.StatusUnsupportedMediaType, GRPCCode: 3, Severity: "warn", Description: "unsupported payload format"},
+		{Kind: ExpectationFailed, Name: "expectation_failed", HTTPStatus: http.StatusExpectationFailed, GRPCCode: 9, Severity: "warn", Description: "expectation could not be met"},
+		{Kind: UnprocessableContent, Name: "unprocessable_content", HTTPStatus: http.StatusUnprocessableEntity, GRPCCode: 3, Severity: "warn", Description: "well-formed but semantically invalid input"},
+		{Kind: TooManyRequests, Name: "too_many_requests", HTTPStatus: http.StatusTooManyRequests, GRPCCode: 8, Retryable: true, Severity: "warn", Description: "rate limit exceeded"},
+		{Kind: NotImplemented, Name: "not_implemented", HTTPStatus: http.StatusNotImplemented, GRPCCode: 12, Severity: "error", Description: "operation not implemented"},
+		{Kind: BadGateway, Name: "bad_gateway", HTTPStatus: http.StatusBadGateway, GRPCCode: 14, Retryable: true, Severity: "error", Description: "invalid response from an upstream"},
+		{Kind: ServiceUnavailable, Name: "service_unavailable", HTTPStatus: http.StatusServiceUnavailable, GRPCCode: 14, Retryable: true, Severity: "error", Description: "dependency temporarily unavailable"},
+		{Kind: Unknown, Name: "unknown", HTTPStatus: http.StatusInternalServerError, GRPCCode: 2, Severity: "error", Description: "unclassified failure"},
+		{Kind: IO, Name: "io", HTTPStatus: http.StatusInternalServerError, GRPCCode: 14, Retryable: true, Severity: "error", Description: "I/O failure"},
+		{Kind: EOF, Name: "eof", HTTPStatus: http.StatusInternalServerError, GRPCCode: 11, Severity: "info", Description: "end of input"},
+	} {
+		RegisterKind(info.Name, info)
+	}
+}
+
+/*
+RegisterKind declares a Kind under a stable name and returns it. Call it from
+a package-level var or init so the Kind is discoverable through ParseKind,
+LookupKind, and Kinds:
+
+	var PaymentDeclined = errnie.RegisterKind("payment_declined", errnie.KindInfo{
+		HTTPStatus: http.StatusPaymentRequired,
+		Severity:   "warn",
+	})
+
+RegisterKind panics when name is empty or already registered, when the Kind is
+already registered under another name, or when the Kind's dynamic type is not
+comparable.
+*/
+func RegisterKind(name string, info KindInfo) Kind {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		panic("errnie: RegisterKind called with an empty name")
+	}
+
+	if info.Kind == nil {
+		info.Kind = errors.New(name)
+	}
+
+	if !reflect.TypeOf(info.Kind).Comparable() {
+		panic("errnie: RegisterKind " + name + ": kind type is not comparable")
+	}
+
+	info.Name = name
+
+	kindRegistryMutex.Lock()
+	defer kindRegistryMutex.Unlock()
+
+	var infos []KindInfo
+	index := map[Kind]int{}
+
+	if current := kindRegistrySnapshot.Load(); current != nil {
+		for _, existing := range current.infos {
+			if existing.Name == name {
+				panic("errnie: kind " + name + " registered twice")
+			}
+
+			if existing.Kind == info.Kind {
+				panic("errnie: kind " + name + " already registered as " + existing.Name)
+			}
+		}
+
+		infos = slices.Clone(current.infos)
+		index = maps.Clone(current.index)
+	}
+
+	index[info.Kind] = len(infos)
+	kindRegistrySnapshot.Store(&kindRegistry{infos: append(infos, info), index: index})
+
+	return info.Kind
+}
+
+/*
+registeredKinds returns the current registry snapshot.
+*/
+func registeredKinds() []KindInfo {
+	if current := kindRegistrySnapshot.Load(); current != nil {
+		return current.infos
+	}
+
+	return nil
+}
+
+/*
+ParseKind returns the registered Kind with the given name.
+*/
+func ParseKind(name string) (Kind, bool) {
+	for _, info := range registeredKinds() {
+		if info.Name == name {
+			return info.Kind, true
+		}
+	}
+
+	return nil, false
+}
+
+/*
+LookupKind returns the registered metadata for kind.
+*/
+func LookupKind(kind Kind) (KindInfo, bool) {
+	if kind == nil {
+		return KindInfo{}, false
+	}
+
+	current := kindRegistrySnapshot.Load()
+	kindType := reflect.TypeOf(kind)
+
+	if current == nil || !kindType.Comparable() {
+		return KindInfo{}, false
+	}
+
+	// Only pointers are always hashable; a comparable struct or array such
+	// as joinedPair can still hold an unhashable error, so it takes the scan.
+	if kindType.Kind() == reflect.Pointer {
+		position, ok := current.index[kind]
+		if !ok {
+			return KindInfo{}, false
+		}
+
+		return current.infos[position], true
+	}
+
+	for index := range current.infos {
+		if current.infos[index].Kind == kind {
+			return current.infos[index], true
+		}
+	}
+
+	return KindInfo{}, false
+}
+
+/*
+KindName returns the registered name of kind, or "unknown" when kind is not
+registered.
+*/
+func KindName(kind Kind) string {
+	if info, ok := LookupKind(kind); ok {
+		return info.Name
+	}
+
+	return "unknown"
+}
+
+/*
+KindForHTTPStatus returns the first registered Kind that maps to status. The
+built-ins are registered so that the general Kind wins a shared status, for
+example BadRequest over Validation for 400 and Internal over IO for 500.
+*/
+func KindForHTTPStatus(status int) (Kind, bool) {
+	for _, info := range registeredKinds() {
+		if info.HTTPStatus == status {
+			return info.Kind, true
+		}
+	}
+
+	return nil, false
+}
+
+/*
+Kinds returns every registered Kind in registration order.
+*/
+func Kinds() []KindInfo {
+	return slices.Clone(registeredKinds())
+}
@@ -0,0 +1,156 @@
+package errnie
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testPaymentDeclined = RegisterKind("test_payment_declined", KindInfo{
+	HTTPStatus: http.StatusPaymentRequired,
+	Severity:   "warn",
+})
+
+/*
+testUncomparableError is an error whose dynamic type cannot be a map key.
+*/
+type testUncomparableError []string
+
+/*
+Error joins the parts.
+*/
+func (err testUncomparableError) Error() string {
+	return strings.Join(err, " ")
+}
+
+/*
+TestKindRegistry verifies the built-in registrations and name lookups.
+*/
+func TestKindRegistry(t *testing.T) {
+	Convey("Given the built-in Kinds", t, func() {
+		Convey("When they are looked up", func() {
+			Convey("Then every built-in should be registered with metadata", func() {
+				for _, kind := range []Kind{
+					Unknown, Validation, IO, EOF, Canceled, DeadlineExceeded,
+					BadRequest, Unauthorized, Forbidden, NotFound, MethodNotAllowed,
+					NotAcceptable, Timeout, Conflict, PreconditionFailed,
+					UnsupportedMedia, ExpectationFailed, UnprocessableContent,
+					TooManyRequests, Internal, NotImplemented, BadGateway,
+					ServiceUnavailable,
+				} {
+					info, ok := LookupKind(kind)
+					So(ok, ShouldBeTrue)
+					So(info.HTTPStatus, ShouldBeGreaterThan, 0)
+
+					parsed, ok := ParseKind(info.Name)
+					So(ok, ShouldBeTrue)
+					So(parsed, ShouldEqual, kind)
+				}
+			})
+
+			Convey("Then transient Kinds should be retryable", func() {
+				for _, kind := range []Kind{Timeout, ServiceUnavailable, TooManyRequests, BadGateway, IO} {
+					info, _ := LookupKind(kind)
+					So(info.Retryable, ShouldBeTrue)
+				}
+
+				info, _ := LookupKind(Validation)
+				So(info.Retryable, ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given Kinds that share a status code", t, func() {
+		Convey("When KindForHTTPStatus is called", func() {
+			Convey("Then the general Kind should win", func() {
+				kind, _ := KindForHTTPStatus(http.StatusBadRequest)
+				So(kind, ShouldEqual, BadRequest)
+
+				kind, _ = KindForHTTPStatus(http.StatusInternalServerError)
+				So(kind, ShouldEqual, Internal)
+
+				kind, _ = KindForHTTPStatus(http.StatusGatewayTimeout)
+				So(kind, ShouldEqual, Timeout)
+			})
+		})
+	})
+
+	Convey("Given unregistered names and values", t, func() {
+		Convey("When they are looked up", func() {
+			_, parsed := ParseKind("nope")
+			_, found := LookupKind(errors.New("nope"))
+			_, foundUncomparable := LookupKind(testUncomparableError{"nope"})
+			joined := Combine(errors.New("a"), testUncomparableError{"x"})
+			_, foundJoined := LookupKind(joined)
+
+			Convey("Then lookups should fail and KindName should fall back", func() {
+				So(parsed, ShouldBeFalse)
+				So(found, ShouldBeFalse)
+				So(foundUncomparable, ShouldBeFalse)
+				So(foundJoined, ShouldBeFalse)
+				So(kindOf(joined), ShouldEqual, Unknown)
+				So(func() { NewCollector(0).Add(joined) }, ShouldNotPanic)
+				So(Result[int]{err: joined}.Recover(func(error) int { return 0 }, NotFound).Err(), ShouldEqual, joined)
+				So(KindName(errors.New("nope")), ShouldEqual, "unknown")
+				So(KindName(nil), ShouldEqual, "unknown")
+			})
+		})
+	})
+}
+
+/*
+TestRegisterKind verifies application-defined Kinds.
+*/
+func TestRegisterKind(t *testing.T) {
+	Convey("Given an application Kind registered at init", t, func() {
+		Convey("When it is looked up and parsed", func() {
+			info, ok := LookupKind(testPaymentDeclined)
+			parsed, _ := ParseKind("test_payment_declined")
+
+			Convey("Then it should be discoverable with its metadata", func() {
+				So(ok, ShouldBeTrue)
+				So(info.Name, ShouldEqual, "test_payment_declined")
+				So(info.HTTPStatus, ShouldEqual, http.StatusPaymentRequired)
+				So(parsed, ShouldEqual, testPaymentDeclined)
+				So(Kinds(), ShouldContain, info)
+			})
+		})
+
+		Convey("When an error of that Kind crosses the JSON wire format", func() {
+			data, err := json.Marshal(Err(testPaymentDeclined, "card declined", nil))
+			So(err, ShouldBeNil)
+
+			var decoded ErrnieError
+			So(json.Unmarshal(data, &decoded), ShouldBeNil)
+
+			Convey("Then the Kind should survive", func() {
+				So(IsKind(&decoded, testPaymentDeclined), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given invalid registrations", t, func() {
+		Convey("When RegisterKind is called", func() {
+			Convey("Then duplicates and empty names should panic", func() {
+				So(func() { RegisterKind("not_found", KindInfo{}) }, ShouldPanic)
+				So(func() { RegisterKind("test_alias", KindInfo{Kind: NotFound}) }, ShouldPanic)
+				So(func() { RegisterKind(" ", KindInfo{}) }, ShouldPanic)
+			})
+		})
+	})
+}
+
+var benchmarkKindInfoSink KindInfo
+
+/*
+BenchmarkLookupKind measures registry metadata lookup.
+*/
+func BenchmarkLookupKind(b *testing.B) {
+	for b.Loop() {
+		benchmarkKindInfoSink, _ = LookupKind(ServiceUnavailable)
+	}
+}
@@ -33,9 +33,15 @@ func init() {
 /*
 Apply reconfigures the global errnie logger from Config. Call after Viper or
 another loader has populated cfg. Configures level, stdout, and optional file
-and Elasticsearch sinks via buildWriter.
+and Elasticsearch sinks via buildWriter, and toggles stack capture for Err and
+Guard and whether fingerprints include the constructing function. It also
+replaces the redaction policy with the one built from cfg.Redaction.
 */
 func Apply(cfg *Config) {
+	stackTraces.Store(cfg.StackTraces)
+	fingerprintLocation.Store(cfg.FingerprintLocation)
+	SetRedactionPolicy(redactionPolicyFromConfig(cfg))
+
 	log.DefaultLogger = log.Logger{
 		Level:      parseLogLevel(cfg.Level),
 		Caller:     loggerCaller(cfg),
@@ -151,6 +157,12 @@ Error logs error at error level with optional alternating key/value fields.
 It explicitly returns the error, which allows it to wrap and log the error
 directly, preventing yet more repetitive error handling code.
 
+Every logged error carries a "fingerprint" field (see Fingerprint) for
+grouping. When err carries a stack trace (see Config.StackTraces), the
+symbolized frames are emitted under the "stack" field. Errors with a Code add
+a "code" field, and errors built with ErrKey add "message_key" and
+"message_args".
+
 Examples:
 
 ```
@@ -190,12 +202,35 @@ func Error(err error, fields ...any) error {
 				logFields = append(append([]any(nil), attached...), fields...)
 			}
 
-			logger.handle.Error().Err(errnieError).KeysAndValues(logFields...).Msg("")
+			logFields = redactFields(logFields)
+
+			digest := fingerprintDigest(err)
+			entry := logger.handle.Error().Err(errnieError).Hex("fingerprint", digest[:])
+
+			if trace := stackOf(err); trace != nil {
+				entry = entry.Str("stack", trace.String())
+			}
+
+			if errnieError.Code != "" {
+				entry = entry.Str("code", string(errnieError.Code))
+			}
+
+			if errnieError.MessageKey != "" {
+				entry = entry.Str("message_key", errnieError.MessageKey)
+
+				if len(errnieError.MessageArgs) > 0 {
+					args := log.NewContext(nil).KeysAndValues(redactFields(errnieError.MessageArgs)...).Value()
+					entry = entry.Dict("message_args", args)
+				}
+			}
+
+			entry.KeysAndValues(logFields...).Msg("")
 
 			return err
 		}
 
-		logger.handle.Error().Err(err).KeysAndValues(fields...).Msg("")
+		digest := fingerprintDigest(err)
+		logger.handle.Error().Err(err).Hex("fingerprint", digest[:]).KeysAndValues(redactFields(fields)...).Msg("")
 	}
 
 	return err
@@ -209,7 +244,7 @@ func Warn(message string, fields ...any) {
 		return
 	}
 
-	logger.handle.Warn().KeysAndValues(fields).Msg(message)
+	logger.handle.Warn().KeysAndValues(redactFields(fields)...).Msg(message)
 }
 
 /*
@@ -220,7 +255,7 @@ func Info(message string, fields ...any) {
 		return
 	}
 
-	logger.handle.Info().KeysAndValues(fields).Msg(message)
+	logger.handle.Info().KeysAndValues(redactFields(fields)...).Msg(message)
 }
 
 /*
@@ -231,7 +266,7 @@ func Debug(message string, fields ...any) {
 		return
 	}
 
-	logger.handle.Debug().KeysAndValues(fields).Msg(message)
+	logger.handle.Debug().KeysAndValues(redactFields(fields)...).Msg(message)
 }
 
 /*
@@ -242,5 +277,5 @@ func Trace(message string, fields ...any) {
 		return
 	}
 
-	logger.handle.Trace().KeysAndValues(fields).Msg(message)
+	logger.handle.Trace().KeysAndValues(redactFields(fields)...).Msg(message)
 }
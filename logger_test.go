@@ -255,6 +255,7 @@ func TestWarn(t *testing.T) {
 
 			Convey("Then it should write a warn log entry", func() {
 				So(buffer.String(), ShouldContainSubstring, "warn message")
+				So(buffer.String(), ShouldContainSubstring, `"key":"value"`)
 			})
 		})
 	})
@@ -286,6 +287,7 @@ func TestInfo(t *testing.T) {
 
 			Convey("Then it should write an info log entry", func() {
 				So(buffer.String(), ShouldContainSubstring, "info message")
+				So(buffer.String(), ShouldContainSubstring, `"key":"value"`)
 			})
 		})
 	})
@@ -0,0 +1,111 @@
+package errnie
+
+import (
+	"sync"
+)
+
+/*
+flight is one in-progress DoesOnce execution that waiters block on.
+*/
+type flight[T any] struct {
+	done   chan struct{}
+	result Result[T]
+	shared *ErrnieError
+}
+
+var (
+	flights      = map[string]any{}
+	flightsMutex sync.Mutex
+)
+
+/*
+DoesOnce is Does with singleflight deduplication: concurrent callers with the
+same key share one execution of fn and all receive its Result, which keeps a
+cache-miss storm from hitting the loader once per goroutine.
+
+	user := errnie.DoesOnce("user:"+id, func() (User, error) {
+		return repo.Find(ctx, id)
+	})
+
+The key is released as soon as fn returns, so later calls run fn again. The
+caller that ran fn gets its error unchanged; every waiter gets its own copy
+marked with the field "shared"=true, so enriching any of them never touches
+another caller's error. A panic in fn becomes an Internal error for every caller, and when fn
+ends its goroutine with runtime.Goexit the waiters get an Internal error, so
+they are never left blocked. A call whose key is in flight for a different T
+does not share and runs fn itself.
+*/
+func DoesOnce[T any](key string, fn func() (T, error)) Result[T] {
+	flightsMutex.Lock()
+
+	if existing, ok := flights[key]; ok {
+		flightsMutex.Unlock()
+
+		call, ok := existing.(*flight[T])
+		if !ok {
+			return doesRecovered(fn)
+		}
+
+		<-call.done
+
+		if call.shared == nil {
+			return call.result
+		}
+
+		return Result[T]{value: call.result.value, err: call.shared.Clone()}
+	}
+
+	call := &flight[T]{done: make(chan struct{})}
+	flights[key] = call
+	flightsMutex.Unlock()
+
+	returned := false
+
+	defer func() {
+		if !returned {
+			call.result = Result[T]{err: Err(Internal, "fn exited without returning", nil)}
+		}
+
+		// The marked copy is made before done is closed, so waiters never
+		// read the error the leader returns and may go on to enrich.
+		call.shared = sharedError(call.result.err)
+
+		flightsMutex.Lock()
+		delete(flights, key)
+		flightsMutex.Unlock()
+
+		close(call.done)
+	}()
+
+	call.result = doesRecovered(fn)
+	returned = true
+
+	return call.result
+}
+
+/*
+doesRecovered is Does with a panic in fn converted into an Internal error.
+*/
+func doesRecovered[T any](fn func() (T, error)) (result Result[T]) {
+	defer Recover(&result.err)
+
+	result.value, result.err = fn()
+
+	return result
+}
+
+/*
+sharedError returns a copy of err marked as shared between DoesOnce callers,
+or nil for a nil err.
+*/
+func sharedError(err error) *ErrnieError {
+	if err == nil {
+		return nil
+	}
+
+	if target, ok := err.(*ErrnieError); ok {
+		return target.Derive("shared", true)
+	}
+
+	return Err(kindOf(err), "", err).With("shared", true)
+}
@@ -0,0 +1,267 @@
+package errnie
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkOnceSink Result[int]
+
+/*
+benchmarkOnceFn is a named loader for BenchmarkDoesOnce.
+*/
+func benchmarkOnceFn() (int, error) {
+	return 42, nil
+}
+
+/*
+onceWaiters counts the goroutines parked in DoesOnce on a flight's done
+channel, read from a dump of every goroutine's stack.
+*/
+func onceWaiters() int {
+	buffer := make([]byte, 1<<20)
+	dump := string(buffer[:runtime.Stack(buffer, true)])
+	waiters := 0
+
+	for _, goroutine := range strings.Split(dump, "\n\n") {
+		header, frames, _ := strings.Cut(goroutine, "\n")
+
+		if strings.Contains(header, "[chan receive") && strings.HasPrefix(frames, "github.com/theapemachine/errnie.DoesOnce[") {
+			waiters++
+		}
+	}
+
+	return waiters
+}
+
+/*
+waitForOnceWaiters blocks until count goroutines wait on a DoesOnce flight,
+failing the test if they do not arrive within ten seconds.
+*/
+func waitForOnceWaiters(t testing.TB, count int) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+
+	for onceWaiters() < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of %d DoesOnce waiters arrived", onceWaiters(), count)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+/*
+runOnceCallers calls DoesOnce with key and fn from callers goroutines and
+returns their results. The first caller runs fn, which must close started and
+then block on release; the rest start once started is closed, and release is
+closed once they all wait on the flight.
+*/
+func runOnceCallers[T any](t testing.TB, callers int, key string, started, release chan struct{}, fn func() (T, error)) []Result[T] {
+	results := make([]Result[T], callers)
+	var workers sync.WaitGroup
+
+	workers.Go(func() { results[0] = DoesOnce(key, fn) })
+	<-started
+
+	for index := 1; index < callers; index++ {
+		workers.Go(func() { results[index] = DoesOnce(key, fn) })
+	}
+
+	waitForOnceWaiters(t, callers-1)
+	close(release)
+
+	workers.Wait()
+	return results
+}
+
+/*
+TestDoesOnce verifies that concurrent callers share one execution.
+*/
+func TestDoesOnce(t *testing.T) {
+	Convey("Given a slow loader called by many goroutines at once", t, func() {
+		var calls atomic.Int64
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		fn := func() (string, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+			}
+
+			<-release
+			return "profile", nil
+		}
+
+		Convey("When they call DoesOnce with the same key", func() {
+			results := runOnceCallers(t, 8, "user:1", started, release, fn)
+
+			Convey("Then the loader should run once and every caller get its value", func() {
+				So(calls.Load(), ShouldEqual, 1)
+
+				for _, result := range results {
+					So(result.Err(), ShouldBeNil)
+					So(result.Value(), ShouldEqual, "profile")
+				}
+			})
+
+			Convey("Then a later call should run the loader again", func() {
+				So(DoesOnce("user:1", fn).Value(), ShouldEqual, "profile")
+				So(calls.Load(), ShouldEqual, 2)
+			})
+		})
+	})
+
+	Convey("Given a shared loader that fails", t, func() {
+		failure := Err(NotFound, "profile missing", nil)
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		fn := func() (int, error) {
+			close(started)
+			<-release
+			return 0, failure
+		}
+
+		Convey("When two callers share it", func() {
+			results := runOnceCallers(t, 2, "user:2", started, release, fn)
+			shared, _ := AsErrnie(results[1].Err())
+
+			Convey("Then the leader should get the original and the waiter a marked copy", func() {
+				So(results[0].Err(), ShouldEqual, failure)
+				So(shared, ShouldNotEqual, failure)
+				So(shared.Fields(), ShouldResemble, []any{"shared", true})
+				So(failure.Fields(), ShouldBeEmpty)
+				So(IsNotFound(shared), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a failing loader whose leader enriches the error it gets back", t, func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		fn := func() (int, error) {
+			close(started)
+			<-release
+			return 0, Err(ServiceUnavailable, "profile store down", nil)
+		}
+
+		Convey("When waiters share it at the same time", func() {
+			var workers sync.WaitGroup
+			waiters := make([]Result[int], 8)
+
+			workers.Go(func() {
+				target, _ := AsErrnie(DoesOnce("user:5", fn).Err())
+				target.With("request_id", "r-1")
+			})
+			<-started
+
+			for index := range waiters {
+				workers.Go(func() { waiters[index] = DoesOnce("user:5", fn) })
+			}
+
+			waitForOnceWaiters(t, len(waiters))
+			close(release)
+			workers.Wait()
+
+			Convey("Then each waiter should hold its own copy with only the marker", func() {
+				So(waiters[0].Err() != waiters[1].Err(), ShouldBeTrue)
+
+				for _, result := range waiters {
+					target, _ := AsErrnie(result.Err())
+					So(target.Fields(), ShouldResemble, []any{"shared", true})
+				}
+			})
+		})
+	})
+
+	Convey("Given a shared loader that panics", t, func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		fn := func() (int, error) {
+			close(started)
+			<-release
+			panic("nil repository")
+		}
+
+		Convey("When two callers share it", func() {
+			results := runOnceCallers(t, 2, "user:3", started, release, fn)
+
+			Convey("Then both should get an Internal error instead of blocking", func() {
+				So(IsInternal(results[0].Err()), ShouldBeTrue)
+				So(IsInternal(results[1].Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a shared loader that exits its goroutine", t, func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var calls atomic.Int64
+
+		fn := func() (int, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-release
+				runtime.Goexit()
+			}
+
+			return 42, nil
+		}
+
+		Convey("When a waiter shares it", func() {
+			waiter := make(chan Result[int], 1)
+
+			go DoesOnce("user:4", fn)
+			<-started
+
+			go func() { waiter <- DoesOnce("user:4", fn) }()
+
+			waitForOnceWaiters(t, 1)
+			close(release)
+
+			Convey("Then the waiter should get an Internal error and the key be released", func() {
+				select {
+				case result := <-waiter:
+					So(IsInternal(result.Err()), ShouldBeTrue)
+				case <-time.After(time.Second):
+					t.Fatal("waiter blocked after the loader exited")
+				}
+
+				So(DoesOnce("user:4", fn).Value(), ShouldEqual, 42)
+			})
+		})
+	})
+
+	Convey("Given a plain error from the loader", t, func() {
+		plain := errors.New("connection reset")
+
+		Convey("When it is shared", func() {
+			err := sharedError(plain)
+
+			Convey("Then the copy should wrap it with the marker", func() {
+				So(errors.Is(err, plain), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "connection reset shared=true")
+			})
+		})
+	})
+}
+
+/*
+BenchmarkDoesOnce measures an uncontended DoesOnce call.
+*/
+func BenchmarkDoesOnce(b *testing.B) {
+	for range b.N {
+		benchmarkOnceSink = DoesOnce("bench", benchmarkOnceFn)
+	}
+}
@@ -0,0 +1,102 @@
+package errnie
+
+import (
+	"context"
+)
+
+/*
+DoesAll runs every fn concurrently and returns their values in argument
+order. The first failure cancels the context the others run under, and DoesAll
+waits for all of them to return. Failures are joined with Combine, each
+wrapped with an "index" field naming its position and keeping its Kind, so
+IsKind still finds them. Context errors from functions that were cancelled
+because of another failure are left out. Use a ResultGroup to label tasks by
+name instead.
+
+	result := errnie.DoesAll(ctx, users.Find, orders.Recent, prefs.Load)
+*/
+func DoesAll[T any](ctx context.Context, fns ...func(context.Context) (T, error)) Result[[]T] {
+	group, _ := NewResultGroup[T](ctx, GroupConfig{})
+
+	for index, fn := range fns {
+		group.Go("", func(ctx context.Context) Result[T] {
+			var value T
+
+			err := runRecovered(ctx, func(ctx context.Context) (err error) {
+				value, err = fn(ctx)
+				return err
+			})
+
+			if err != nil {
+				err = indexedError(err, index)
+			}
+
+			return Result[T]{value: value, err: err}
+		})
+	}
+
+	return group.Wait()
+}
+
+/*
+DoesAny runs every fn concurrently and returns the first success, cancelling
+the others. When every fn fails, the failures are joined like DoesAll, in
+argument order. Without functions it fails with Validation.
+
+	result := errnie.DoesAny(ctx, primary.Get, replica.Get)
+*/
+func DoesAny[T any](ctx context.Context, fns ...func(context.Context) (T, error)) Result[T] {
+	if len(fns) == 0 {
+		return Result[T]{err: Err(Validation, "no functions to run", nil).Operation("does.any")}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return AwaitAny(ctx, startAll(ctx, fns)...)
+}
+
+/*
+DoesRace runs every fn concurrently and returns the first to finish, success
+or failure, cancelling the others. A failing winner is wrapped with its
+"index" field like DoesAll. Without functions it fails with Validation.
+*/
+func DoesRace[T any](ctx context.Context, fns ...func(context.Context) (T, error)) Result[T] {
+	if len(fns) == 0 {
+		return Result[T]{err: Err(Validation, "no functions to run", nil).Operation("does.race")}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	futures := startAll(ctx, fns)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	select {
+	case index := <-completions(futures, stop):
+		result := futures[index].result
+
+		if result.err != nil {
+			result.err = indexedError(result.err, index)
+		}
+
+		return result
+	case <-ctx.Done():
+		return Result[T]{err: abandonedError(ctx, "does.race")}
+	}
+}
+
+/*
+startAll starts every fn with DoesAsync under ctx.
+*/
+func startAll[T any](ctx context.Context, fns []func(context.Context) (T, error)) []*Future[T] {
+	futures := make([]*Future[T], len(fns))
+
+	for index, fn := range fns {
+		futures[index] = DoesAsync(ctx, fn)
+	}
+
+	return futures
+}
@@ -0,0 +1,200 @@
+package errnie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkParallelSink Result[[]int]
+
+/*
+TestDoesAll verifies ordered values, cancellation, and indexed failures.
+*/
+func TestDoesAll(t *testing.T) {
+	Convey("Given functions that all succeed out of order", t, func() {
+		fns := make([]func(context.Context) (int, error), 4)
+
+		for index := range fns {
+			fns[index] = func(context.Context) (int, error) {
+				time.Sleep(time.Duration(4-index) * time.Millisecond)
+				return index * 10, nil
+			}
+		}
+
+		Convey("When DoesAll runs them", func() {
+			result := DoesAll(context.Background(), fns...)
+
+			Convey("Then the values should be in argument order", func() {
+				So(result.Err(), ShouldBeNil)
+				So(result.Value(), ShouldResemble, []int{0, 10, 20, 30})
+			})
+		})
+	})
+
+	Convey("Given one failing function among slow ones", t, func() {
+		slow := func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+
+		failing := func(context.Context) (int, error) {
+			return 0, Err(NotFound, "profile missing", nil)
+		}
+
+		Convey("When DoesAll runs them", func() {
+			result := DoesAll(context.Background(), slow, failing, slow)
+
+			Convey("Then the others should be cancelled and only the failure reported", func() {
+				So(result.Err().Error(), ShouldEqual, "profile missing index=1")
+				So(IsNotFound(result.Err()), ShouldBeTrue)
+				So(IsKind(result.Err(), NotFound), ShouldBeTrue)
+				So(result.Value(), ShouldHaveLength, 3)
+			})
+		})
+	})
+
+	Convey("Given a function that panics", t, func() {
+		Convey("When DoesAll runs it", func() {
+			result := DoesAll(context.Background(), func(context.Context) (int, error) {
+				panic("boom")
+			})
+
+			Convey("Then the panic should be an indexed Internal error", func() {
+				target, _ := AsErrnie(result.Err())
+				So(IsInternal(result.Err()), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"index", 0})
+			})
+		})
+	})
+}
+
+/*
+TestDoesAny verifies first-success selection and aggregated failures.
+*/
+func TestDoesAny(t *testing.T) {
+	Convey("Given a failing primary and a healthy replica", t, func() {
+		loserCancelled := make(chan struct{})
+
+		primary := func(context.Context) (string, error) {
+			return "", Err(ServiceUnavailable, "primary down", nil)
+		}
+
+		replica := func(context.Context) (string, error) {
+			time.Sleep(time.Millisecond)
+			return "replica", nil
+		}
+
+		stuck := func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			close(loserCancelled)
+			return "", ctx.Err()
+		}
+
+		Convey("When DoesAny runs them", func() {
+			result := DoesAny(context.Background(), primary, replica, stuck)
+
+			Convey("Then the success should win and the loser be cancelled", func() {
+				So(result.Err(), ShouldBeNil)
+				So(result.Value(), ShouldEqual, "replica")
+
+				select {
+				case <-loserCancelled:
+				case <-time.After(time.Second):
+					t.Error("loser was not cancelled")
+				}
+			})
+		})
+	})
+
+	Convey("Given functions that all fail", t, func() {
+		Convey("When DoesAny runs them", func() {
+			result := DoesAny(context.Background(),
+				func(context.Context) (int, error) { return 0, Err(Timeout, "slow", nil) },
+				func(context.Context) (int, error) { return 0, Err(Forbidden, "denied", nil) },
+			)
+
+			Convey("Then every Kind should be findable in the joined error", func() {
+				So(result.Err().Error(), ShouldEqual, "slow index=0\ndenied index=1")
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+				So(IsForbidden(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given no functions", t, func() {
+		Convey("When DoesAny and DoesRace are called", func() {
+			Convey("Then they should fail with Validation", func() {
+				So(IsValidation(DoesAny[int](context.Background()).Err()), ShouldBeTrue)
+				So(IsValidation(DoesRace[int](context.Background()).Err()), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+TestDoesRace verifies that the fastest function wins, success or failure.
+*/
+func TestDoesRace(t *testing.T) {
+	slow := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	Convey("Given a fast success and a slow function", t, func() {
+		Convey("When DoesRace runs them", func() {
+			result := DoesRace(context.Background(), slow, func(context.Context) (string, error) {
+				return "fast", nil
+			})
+
+			Convey("Then the fast value should win", func() {
+				So(result.Err(), ShouldBeNil)
+				So(result.Value(), ShouldEqual, "fast")
+			})
+		})
+	})
+
+	Convey("Given a fast failure and a slow function", t, func() {
+		Convey("When DoesRace runs them", func() {
+			result := DoesRace(context.Background(), slow, func(context.Context) (string, error) {
+				return "", Err(BadGateway, "upstream broke", nil)
+			})
+
+			Convey("Then the failure should win with its index", func() {
+				So(result.Err().Error(), ShouldEqual, "upstream broke index=1")
+				So(IsBadGateway(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a caller context that ends during the race", t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		ignoring := func(context.Context) (string, error) {
+			time.Sleep(50 * time.Millisecond)
+			return "late", nil
+		}
+
+		Convey("When DoesRace runs functions that ignore it", func() {
+			result := DoesRace(ctx, ignoring, ignoring)
+
+			Convey("Then the race should be abandoned with DeadlineExceeded", func() {
+				So(IsDeadlineExceeded(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkDoesAll measures running a small batch of functions to completion.
+*/
+func BenchmarkDoesAll(b *testing.B) {
+	ctx := context.Background()
+
+	for range b.N {
+		benchmarkParallelSink = DoesAll(ctx, benchmarkAsyncFn, benchmarkAsyncFn, benchmarkAsyncFn)
+	}
+}
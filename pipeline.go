@@ -0,0 +1,83 @@
+package errnie
+
+import (
+	"context"
+)
+
+/*
+Step is one named stage of a Pipeline. Compensate, when set, undoes a
+completed Action if a later step fails.
+*/
+type Step struct {
+	// Name identifies the step and becomes the Op of its errors.
+	Name string
+	// Action performs the step.
+	Action func(context.Context) error
+	// Compensate rolls the step back. Optional.
+	Compensate func(context.Context) error
+}
+
+/*
+Pipeline runs steps in order and rolls back on failure, saga style:
+
+	err := errnie.Pipeline{
+		{Name: "user.create", Action: createUser, Compensate: deleteUser},
+		{Name: "card.charge", Action: chargeCard, Compensate: refundCard},
+		{Name: "mail.send", Action: sendWelcome},
+	}.Run(ctx)
+*/
+type Pipeline []Step
+
+/*
+Run executes the steps in order. When a step fails, or ctx is done before it
+starts, the completed steps are compensated in reverse order under
+context.WithoutCancel, so rollback still runs after a cancellation. Run then
+returns an ErrnieError with the failure's Kind, the failing step's Name as Op,
+the field "compensated" counting successful rollbacks, and a cause that joins
+the failure with any compensation failures through Combine. Each compensation
+failure has its step's Name as Op. Panics in actions and compensations become
+Internal errors.
+*/
+func (pipeline Pipeline) Run(ctx context.Context) error {
+	for index, step := range pipeline {
+		failure := ctx.Err()
+
+		if failure != nil {
+			failure = context.Cause(ctx)
+		} else if step.Action != nil {
+			failure = runRecovered(ctx, step.Action)
+		}
+
+		if failure != nil {
+			return pipeline[:index].compensate(context.WithoutCancel(ctx), step.Name, failure)
+		}
+	}
+
+	return nil
+}
+
+/*
+compensate rolls back the completed steps in reverse and builds the error for
+the failing step.
+*/
+func (pipeline Pipeline) compensate(ctx context.Context, op string, failure error) *ErrnieError {
+	errs := []error{failure}
+	compensated := 0
+
+	for index := len(pipeline) - 1; index >= 0; index-- {
+		step := pipeline[index]
+
+		if step.Compensate == nil {
+			continue
+		}
+
+		if err := runRecovered(ctx, step.Compensate); err != nil {
+			errs = append(errs, Err(kindOf(err), "compensation failed", err).Operation(step.Name))
+			continue
+		}
+
+		compensated++
+	}
+
+	return Err(kindOf(failure), "", Combine(errs...)).Operation(op).With("compensated", compensated)
+}
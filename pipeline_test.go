@@ -0,0 +1,173 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkPipelineSink error
+
+/*
+benchmarkStepFn is a named no-op action for BenchmarkPipeline.
+*/
+func benchmarkStepFn(context.Context) error {
+	return nil
+}
+
+/*
+recordingStep returns a Step that appends its actions and rollbacks to log.
+*/
+func recordingStep(log *[]string, name string, fail error, compensationFail error) Step {
+	return Step{
+		Name: name,
+		Action: func(context.Context) error {
+			*log = append(*log, name)
+			return fail
+		},
+		Compensate: func(ctx context.Context) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			*log = append(*log, "undo "+name)
+			return compensationFail
+		},
+	}
+}
+
+/*
+TestPipeline verifies ordered execution and reverse compensation.
+*/
+func TestPipeline(t *testing.T) {
+	Convey("Given a pipeline whose steps all succeed", t, func() {
+		var log []string
+
+		pipeline := Pipeline{
+			recordingStep(&log, "user.create", nil, nil),
+			recordingStep(&log, "card.charge", nil, nil),
+		}
+
+		Convey("When it runs", func() {
+			err := pipeline.Run(context.Background())
+
+			Convey("Then every action should run in order without rollback", func() {
+				So(err, ShouldBeNil)
+				So(log, ShouldResemble, []string{"user.create", "card.charge"})
+			})
+		})
+	})
+
+	Convey("Given a pipeline whose third step fails", t, func() {
+		var log []string
+
+		declined := Err(Validation, "card declined", nil)
+
+		pipeline := Pipeline{
+			recordingStep(&log, "user.create", nil, nil),
+			{Name: "audit.write", Action: func(context.Context) error { log = append(log, "audit.write"); return nil }},
+			recordingStep(&log, "card.charge", declined, nil),
+			recordingStep(&log, "mail.send", nil, nil),
+		}
+
+		Convey("When it runs", func() {
+			err := pipeline.Run(context.Background())
+			target, _ := AsErrnie(err)
+
+			Convey("Then completed steps should be rolled back in reverse", func() {
+				So(log, ShouldResemble, []string{"user.create", "audit.write", "card.charge", "undo user.create"})
+			})
+
+			Convey("Then the error should name the failing step and keep its Kind", func() {
+				So(target.Op, ShouldEqual, "card.charge")
+				So(IsValidation(err), ShouldBeTrue)
+				So(errors.Is(err, declined), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "card.charge: card declined compensated=1")
+			})
+		})
+	})
+
+	Convey("Given a pipeline whose compensations also fail", t, func() {
+		var log []string
+
+		pipeline := Pipeline{
+			recordingStep(&log, "user.create", nil, Err(IO, "delete failed", nil)),
+			recordingStep(&log, "seat.reserve", nil, nil),
+			recordingStep(&log, "card.charge", Err(ServiceUnavailable, "gateway down", nil), nil),
+		}
+
+		Convey("When it runs", func() {
+			err := pipeline.Run(context.Background())
+			target, _ := AsErrnie(err)
+			branches := target.Cause.(interface{ Unwrap() []error }).Unwrap()
+
+			Convey("Then the cause should join the failure with the compensation failure", func() {
+				So(IsServiceUnavailable(err), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"compensated", 1})
+				So(branches, ShouldHaveLength, 2)
+				So(branches[1].Error(), ShouldEqual, "user.create: compensation failed")
+				So(IsIO(branches[1]), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a context cancelled by the second step", t, func() {
+		var log []string
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		pipeline := Pipeline{
+			recordingStep(&log, "user.create", nil, nil),
+			{Name: "shutdown", Action: func(context.Context) error { cancel(); return nil }},
+			recordingStep(&log, "card.charge", nil, nil),
+		}
+
+		Convey("When it runs", func() {
+			err := pipeline.Run(ctx)
+			target, _ := AsErrnie(err)
+
+			Convey("Then the next step should fail with Canceled and rollback still run", func() {
+				So(IsCanceled(err), ShouldBeTrue)
+				So(target.Op, ShouldEqual, "card.charge")
+				So(log, ShouldResemble, []string{"user.create", "undo user.create"})
+			})
+		})
+	})
+
+	Convey("Given a step that panics", t, func() {
+		var log []string
+
+		pipeline := Pipeline{
+			recordingStep(&log, "user.create", nil, nil),
+			{Name: "card.charge", Action: func(context.Context) error { panic("nil gateway") }},
+		}
+
+		Convey("When it runs", func() {
+			err := pipeline.Run(context.Background())
+
+			Convey("Then the panic should fail the step and trigger rollback", func() {
+				So(IsInternal(err), ShouldBeTrue)
+				So(log, ShouldResemble, []string{"user.create", "undo user.create"})
+			})
+		})
+	})
+}
+
+/*
+BenchmarkPipeline measures a successful three-step run.
+*/
+func BenchmarkPipeline(b *testing.B) {
+	ctx := context.Background()
+	pipeline := Pipeline{
+		{Name: "a", Action: benchmarkStepFn, Compensate: benchmarkStepFn},
+		{Name: "b", Action: benchmarkStepFn, Compensate: benchmarkStepFn},
+		{Name: "c", Action: benchmarkStepFn},
+	}
+
+	for range b.N {
+		benchmarkPipelineSink = pipeline.Run(ctx)
+	}
+}
@@ -0,0 +1,113 @@
+package errnie
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+/*
+Recover converts a panic in the surrounding function into an Internal
+ErrnieError stored in *target. It must be deferred directly:
+
+	func (worker *Worker) step() (err error) {
+		defer errnie.Recover(&err)
+		...
+	}
+
+The error carries the formatted panic value under the "panic" field and the
+stack of the panicking goroutine, whether or not stack traces are enabled. A
+panic value that is an error becomes the cause, so errors.Is and errors.As
+still see it. An error already stored in *target is kept alongside through
+Combine. A nil target logs the error through Error instead. Without a panic
+Recover does nothing.
+*/
+func Recover(target *error) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	err := panicError(value, 1)
+
+	if target == nil {
+		Error(err)
+		return
+	}
+
+	*target = Combine(*target, err)
+}
+
+/*
+Go runs fn in a new goroutine with panic recovery. A non-nil error, including
+one produced by a panic, is logged through Error and then passed to each
+supervisor in order, for example to cancel a parent context or count failures.
+
+	errnie.Go(ctx, consumer.Run, func(err error) {
+		metrics.WorkerFailures.Inc()
+	})
+*/
+func Go(ctx context.Context, fn func(context.Context) error, supervisors ...func(error)) {
+	go func() {
+		err := runRecovered(ctx, fn)
+		if err == nil {
+			return
+		}
+
+		Error(err)
+
+		for _, supervisor := range supervisors {
+			supervisor(err)
+		}
+	}()
+}
+
+/*
+runRecovered calls fn and converts a panic into its returned error.
+*/
+func runRecovered(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer Recover(&err)
+
+	return fn(ctx)
+}
+
+/*
+panicError builds the Internal error for a recovered panic value. skip drops
+frames above panicError's caller; the runtime's panic frames are trimmed so the
+trace starts at the function that panicked.
+*/
+func panicError(value any, skip int) *ErrnieError {
+	cause, _ := value.(error)
+
+	err := &ErrnieError{
+		Kind:    Internal,
+		Message: "panic recovered",
+		Cause:   cause,
+		fields:  []any{"panic", fmt.Sprint(value)},
+		stack:   captureStack(skip + 1),
+	}
+
+	err.stack.trimRuntime()
+
+	return err
+}
+
+/*
+trimRuntime drops the leading runtime frames (gopanic, sigpanic, and friends)
+so a recovered trace starts at the panicking function.
+*/
+func (trace *stackTrace) trimRuntime() {
+	if trace == nil {
+		return
+	}
+
+	for index, pc := range trace.pcs {
+		function := runtime.FuncForPC(pc - 1)
+
+		if function == nil || !strings.HasPrefix(function.Name(), "runtime.") {
+			trace.pcs = trace.pcs[index:]
+			return
+		}
+	}
+}
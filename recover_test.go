@@ -0,0 +1,200 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkRecoverSink error
+
+/*
+panicking panics with value from inside a function that defers Recover.
+*/
+func panicking(value any) (err error) {
+	defer Recover(&err)
+
+	panic(value)
+}
+
+/*
+TestRecover verifies that deferred Recover turns panics into Internal errors.
+*/
+func TestRecover(t *testing.T) {
+	Convey("Given a function that panics with a string", t, func() {
+		Convey("When Recover is deferred", func() {
+			err := panicking("boom")
+			target, ok := AsErrnie(err)
+
+			Convey("Then an Internal error should carry the value and stack", func() {
+				So(ok, ShouldBeTrue)
+				So(IsInternal(err), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"panic", "boom"})
+				So(target.Error(), ShouldEqual, "panic recovered panic=boom")
+				So(stackOf(err).Frames(), ShouldNotBeEmpty)
+				So(stackOf(err).Frames()[0].Function, ShouldEndWith, "errnie.panicking")
+			})
+		})
+	})
+
+	Convey("Given a function that panics with an error", t, func() {
+		cause := errors.New("invariant broken")
+
+		Convey("When Recover is deferred", func() {
+			err := panicking(cause)
+
+			Convey("Then the panic value should be the cause", func() {
+				So(errors.Is(err, cause), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a runtime panic", t, func() {
+		Convey("When Recover is deferred", func() {
+			err := func() (err error) {
+				defer Recover(&err)
+
+				var values map[string]int
+				values["boom"]++
+
+				return nil
+			}()
+
+			Convey("Then the trace should start at the faulting function, not the runtime", func() {
+				So(IsInternal(err), ShouldBeTrue)
+				So(strings.HasPrefix(stackOf(err).Frames()[0].Function, "runtime."), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a function that set an error before panicking", t, func() {
+		Convey("When Recover is deferred", func() {
+			err := func() (err error) {
+				defer Recover(&err)
+
+				err = Err(IO, "write failed", nil)
+				panic("cleanup")
+			}()
+
+			Convey("Then both errors should be kept", func() {
+				So(IsIO(err), ShouldBeTrue)
+				So(IsInternal(err), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a function that does not panic", t, func() {
+		Convey("When Recover is deferred", func() {
+			err := func() (err error) {
+				defer Recover(&err)
+				return nil
+			}()
+
+			Convey("Then the error should stay nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given Recover deferred with a nil target", t, func() {
+		buffer := configureTestLogger(t, log.ErrorLevel)
+
+		Convey("When the function panics", func() {
+			func() {
+				defer Recover(nil)
+				panic("detached")
+			}()
+
+			Convey("Then the error should be logged", func() {
+				So(buffer.String(), ShouldContainSubstring, `"panic":"detached"`)
+				So(buffer.String(), ShouldContainSubstring, `"stack":`)
+			})
+		})
+	})
+}
+
+/*
+TestGo verifies the supervised goroutine launcher.
+*/
+func TestGo(t *testing.T) {
+	Convey("Given a goroutine that panics", t, func() {
+		buffer := configureTestLogger(t, log.ErrorLevel)
+		reported := make(chan error, 2)
+
+		Convey("When it is started with Go and two supervisors", func() {
+			Go(context.Background(), func(context.Context) error {
+				panic("worker crashed")
+			}, func(err error) {
+				reported <- err
+			}, func(err error) {
+				reported <- Err(Internal, "second", err)
+			})
+
+			first := <-reported
+			second := <-reported
+
+			Convey("Then the error should be logged and reported to each supervisor in order", func() {
+				So(IsInternal(first), ShouldBeTrue)
+				So(errors.Is(second, first), ShouldBeTrue)
+				So(buffer.String(), ShouldContainSubstring, `"panic":"worker crashed"`)
+			})
+		})
+	})
+
+	Convey("Given a goroutine that returns an error", t, func() {
+		configureTestLogger(t, log.ErrorLevel)
+		reported := make(chan error, 1)
+		ctx := WithLocale(context.Background(), "de")
+
+		Convey("When it is started with Go", func() {
+			Go(ctx, func(ctx context.Context) error {
+				return Err(Validation, "bad input", nil).With("locale", LocaleFrom(ctx))
+			}, func(err error) {
+				reported <- err
+			})
+
+			err := <-reported
+
+			Convey("Then the context should be passed and the error reported unchanged", func() {
+				So(IsValidation(err), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "bad input locale=de")
+			})
+		})
+	})
+
+	Convey("Given a goroutine that succeeds", t, func() {
+		buffer := configureTestLogger(t, log.ErrorLevel)
+		done := make(chan struct{})
+
+		Convey("When it is started with Go", func() {
+			Go(context.Background(), func(context.Context) error {
+				defer close(done)
+				return nil
+			}, func(error) {
+				t.Error("supervisor called for a nil error")
+			})
+
+			<-done
+
+			Convey("Then nothing should be logged", func() {
+				So(buffer.Len(), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkRecover measures the deferred Recover on the non-panicking path.
+*/
+func BenchmarkRecover(b *testing.B) {
+	for range b.N {
+		benchmarkRecoverSink = func() (err error) {
+			defer Recover(&err)
+			return nil
+		}()
+	}
+}
@@ -0,0 +1,263 @@
+package errnie
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+DefaultRedactionMask replaces redacted values when a policy sets no Mask.
+*/
+const DefaultRedactionMask = "[REDACTED]"
+
+/*
+Secret marks a value that must never be rendered. Attach credentials as
+errnie.Secret(token) and every formatter masks them: fmt verbs, JSON, slog,
+ErrnieError.Error(), and the errnie log sinks. Use Reveal to read the value.
+*/
+type Secret string
+
+/*
+Reveal returns the underlying value.
+*/
+func (secret Secret) Reveal() string {
+	return string(secret)
+}
+
+/*
+String returns the redaction mask.
+*/
+func (secret Secret) String() string {
+	return redactionPolicySnapshot().mask()
+}
+
+/*
+GoString returns the redaction mask, so %#v does not reveal the value either.
+*/
+func (secret Secret) GoString() string {
+	return secret.String()
+}
+
+/*
+Format implements fmt.Formatter and writes the mask for every verb.
+*/
+func (secret Secret) Format(state fmt.State, _ rune) {
+	io.WriteString(state, secret.String())
+}
+
+/*
+MarshalJSON encodes the mask as a JSON string.
+*/
+func (secret Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(secret.String())
+}
+
+/*
+LogValue implements slog.LogValuer and logs the mask.
+*/
+func (secret Secret) LogValue() slog.Value {
+	return slog.StringValue(secret.String())
+}
+
+/*
+RedactionPolicy decides which field values are masked before they are
+rendered or logged. Secret values are always masked, with or without a policy.
+*/
+type RedactionPolicy struct {
+	// Keys lists field names, matched case-insensitively, whose values are
+	// always masked.
+	Keys []string
+	// Patterns are matched against string, error, and fmt.Stringer values;
+	// each match is replaced by Mask and the rest of the value is kept.
+	Patterns []*regexp.Regexp
+	// Mask replaces redacted values. Defaults to DefaultRedactionMask.
+	Mask string
+}
+
+/*
+redactionPolicy holds the active policy. A nil pointer means no policy, which
+keeps the common case to one atomic load and a type check per field.
+*/
+var redactionPolicy atomic.Pointer[RedactionPolicy]
+
+/*
+SetRedactionPolicy replaces the active redaction policy. Apply calls it with
+the policy built from Config.Redaction.
+*/
+func SetRedactionPolicy(policy RedactionPolicy) {
+	if len(policy.Keys) == 0 && len(policy.Patterns) == 0 && policy.Mask == "" {
+		redactionPolicy.Store(nil)
+		return
+	}
+
+	policy.Keys = append([]string(nil), policy.Keys...)
+	policy.Patterns = append([]*regexp.Regexp(nil), policy.Patterns...)
+	redactionPolicy.Store(&policy)
+}
+
+/*
+Redact returns value as it may be rendered under key: the mask for a Secret or
+a denied key, value with pattern matches masked, or value unchanged.
+*/
+func Redact(key string, value any) any {
+	redacted, _ := redactionPolicySnapshot().redact(key, value)
+
+	return redacted
+}
+
+/*
+redactionPolicySnapshot returns the active policy, or nil.
+*/
+func redactionPolicySnapshot() *RedactionPolicy {
+	return redactionPolicy.Load()
+}
+
+/*
+redactionPolicyFromConfig compiles the policy configured in cfg. Invalid
+patterns are reported on stderr and skipped, the same way Apply reports an
+unusable Elasticsearch sink.
+*/
+func redactionPolicyFromConfig(cfg *Config) RedactionPolicy {
+	policy := RedactionPolicy{
+		Keys: cfg.Redaction.Keys,
+		Mask: cfg.Redaction.Mask,
+	}
+
+	for _, expression := range cfg.Redaction.Patterns {
+		pattern, err := regexp.Compile(expression)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "errnie: redaction pattern %q: %v\n", expression, err)
+			continue
+		}
+
+		policy.Patterns = append(policy.Patterns, pattern)
+	}
+
+	return policy
+}
+
+/*
+mask returns the configured mask or DefaultRedactionMask. It is safe on a nil
+policy.
+*/
+func (policy *RedactionPolicy) mask() string {
+	if policy == nil || policy.Mask == "" {
+		return DefaultRedactionMask
+	}
+
+	return policy.Mask
+}
+
+/*
+deniesKey reports whether key is on the deny-list.
+*/
+func (policy *RedactionPolicy) deniesKey(key string) bool {
+	if policy == nil {
+		return false
+	}
+
+	for _, denied := range policy.Keys {
+		if strings.EqualFold(denied, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+redactString masks every pattern match in text and reports whether anything
+matched. Text without a match is returned without allocating.
+*/
+func (policy *RedactionPolicy) redactString(text string) (string, bool) {
+	if policy == nil {
+		return text, false
+	}
+
+	changed := false
+
+	for _, pattern := range policy.Patterns {
+		if pattern.MatchString(text) {
+			text = pattern.ReplaceAllLiteralString(text, policy.mask())
+			changed = true
+		}
+	}
+
+	return text, changed
+}
+
+/*
+redact applies the policy to one field and reports whether the value changed.
+It is safe on a nil policy, which still masks Secret values.
+*/
+func (policy *RedactionPolicy) redact(key string, value any) (any, bool) {
+	if _, ok := value.(Secret); ok {
+		return policy.mask(), true
+	}
+
+	if policy == nil {
+		return value, false
+	}
+
+	if policy.deniesKey(key) {
+		return policy.mask(), true
+	}
+
+	if len(policy.Patterns) == 0 {
+		return value, false
+	}
+
+	var text string
+
+	switch typed := value.(type) {
+	case string:
+		text = typed
+	case error:
+		text = typed.Error()
+	case fmt.Stringer:
+		text = typed.String()
+	default:
+		return value, false
+	}
+
+	if redacted, changed := policy.redactString(text); changed {
+		return redacted, true
+	}
+
+	return value, false
+}
+
+/*
+redactFields applies the policy to alternating key/value fields. It returns
+fields itself when nothing is redacted and a redacted copy otherwise, so the
+caller's slice, often an ErrnieError's field storage, is never modified.
+*/
+func redactFields(fields []any) []any {
+	policy := redactionPolicySnapshot()
+	redacted := fields
+	copied := false
+
+	for index := 0; index+1 < len(fields); index += 2 {
+		key, _ := fields[index].(string)
+
+		value, changed := policy.redact(key, fields[index+1])
+		if !changed {
+			continue
+		}
+
+		if !copied {
+			redacted = append([]any(nil), fields...)
+			copied = true
+		}
+
+		redacted[index+1] = value
+	}
+
+	return redacted
+}
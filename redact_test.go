@@ -0,0 +1,221 @@
+package errnie
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var benchmarkRedactSink []any
+
+/*
+setTestRedactionPolicy installs policy for the duration of a test.
+*/
+func setTestRedactionPolicy(t testing.TB, policy RedactionPolicy) {
+	t.Helper()
+
+	previous := redactionPolicy.Load()
+	SetRedactionPolicy(policy)
+
+	t.Cleanup(func() {
+		redactionPolicy.Store(previous)
+	})
+}
+
+/*
+TestSecret verifies that Secret never renders its value.
+*/
+func TestSecret(t *testing.T) {
+	Convey("Given a Secret", t, func() {
+		secret := Secret("hunter2")
+
+		Convey("When it is formatted, encoded, and logged", func() {
+			encoded, err := json.Marshal(map[string]any{"password": secret})
+
+			Convey("Then every rendering should show the mask", func() {
+				for _, verb := range []string{"%v", "%s", "%q", "%#v", "%x", "%+v"} {
+					So(fmt.Sprintf(verb, secret), ShouldEqual, DefaultRedactionMask)
+				}
+
+				So(err, ShouldBeNil)
+				So(string(encoded), ShouldEqual, `{"password":"[REDACTED]"}`)
+				So(slog.AnyValue(secret).Resolve().String(), ShouldEqual, DefaultRedactionMask)
+			})
+
+			Convey("Then Reveal should return the value", func() {
+				So(secret.Reveal(), ShouldEqual, "hunter2")
+			})
+		})
+
+		Convey("When a policy sets a custom mask", func() {
+			setTestRedactionPolicy(t, RedactionPolicy{Mask: "***"})
+
+			Convey("Then the custom mask should be used", func() {
+				So(secret.String(), ShouldEqual, "***")
+			})
+		})
+	})
+}
+
+/*
+TestRedactionPolicy verifies key and pattern redaction across renderings.
+*/
+func TestRedactionPolicy(t *testing.T) {
+	Convey("Given a policy with denied keys and a bearer token pattern", t, func() {
+		setTestRedactionPolicy(t, RedactionPolicy{
+			Keys:     []string{"password", "api_key"},
+			Patterns: []*regexp.Regexp{regexp.MustCompile(`Bearer [A-Za-z0-9._-]+`)},
+		})
+
+		err := Err(Unauthorized, "login failed", nil).With(
+			"user", "ernie",
+			"Password", "hunter2",
+			"header", "Authorization: Bearer abc.def",
+			"token", Secret("t0k3n"),
+		)
+
+		Convey("When Error renders the fields", func() {
+			text := err.Error()
+
+			Convey("Then denied keys, matches, and secrets should be masked", func() {
+				So(text, ShouldEqual, "login failed user=ernie Password=[REDACTED] header=Authorization: [REDACTED] token=[REDACTED]")
+			})
+
+			Convey("Then the stored fields should be untouched", func() {
+				So(err.Fields()[3], ShouldEqual, "hunter2")
+			})
+		})
+
+		Convey("When the error is formatted as a tree", func() {
+			Convey("Then the fields line should be masked", func() {
+				So(fmt.Sprintf("%+v", err), ShouldContainSubstring, "Password=[REDACTED]")
+				So(fmt.Sprintf("%+v", err), ShouldNotContainSubstring, "hunter2")
+			})
+		})
+
+		Convey("When the error is encoded as JSON", func() {
+			encoded, encodeErr := json.Marshal(err)
+
+			Convey("Then no secret should cross the wire", func() {
+				So(encodeErr, ShouldBeNil)
+				So(string(encoded), ShouldNotContainSubstring, "hunter2")
+				So(string(encoded), ShouldNotContainSubstring, "abc.def")
+				So(string(encoded), ShouldNotContainSubstring, "t0k3n")
+			})
+		})
+
+		Convey("When the error and extra fields are logged through Error and Warn", func() {
+			buffer := configureTestLogger(t, log.InfoLevel)
+			Error(err, "api_key", "k-123")
+			Warn("retrying", "API_KEY", "k-456", "attempt", 2)
+
+			Convey("Then no sink output should contain a secret", func() {
+				line := buffer.String()
+				So(line, ShouldNotContainSubstring, "hunter2")
+				So(line, ShouldNotContainSubstring, "abc.def")
+				So(line, ShouldNotContainSubstring, "t0k3n")
+				So(line, ShouldNotContainSubstring, "k-123")
+				So(line, ShouldNotContainSubstring, "k-456")
+				So(line, ShouldContainSubstring, `"user":"ernie"`)
+				So(line, ShouldContainSubstring, `"attempt":2`)
+			})
+		})
+
+		Convey("When slog records carry secrets", func() {
+			buffer := configureTestLogger(t, log.InfoLevel)
+			slog.New(NewSlogHandler(nil)).Info("call", "password", "hunter2", "auth", "Bearer abc.def", "err", err)
+
+			Convey("Then the handler should mask them", func() {
+				line := buffer.String()
+				So(line, ShouldNotContainSubstring, "hunter2")
+				So(line, ShouldNotContainSubstring, "abc.def")
+				So(line, ShouldNotContainSubstring, "t0k3n")
+				So(line, ShouldContainSubstring, `"err.fields.user":"ernie"`)
+			})
+		})
+	})
+
+	Convey("Given fields without anything to redact", t, func() {
+		fields := []any{"user", "ernie", "attempt", 2}
+
+		Convey("When they are redacted with and without a policy", func() {
+			withoutPolicy := redactFields(fields)
+			setTestRedactionPolicy(t, RedactionPolicy{Keys: []string{"password"}})
+			withPolicy := redactFields(fields)
+
+			Convey("Then the same slice should be returned", func() {
+				So(&withoutPolicy[0], ShouldEqual, &fields[0])
+				So(&withPolicy[0], ShouldEqual, &fields[0])
+			})
+		})
+	})
+
+	Convey("Given an error rendered before a policy is applied", t, func() {
+		setTestRedactionPolicy(t, RedactionPolicy{})
+		buffer := configureTestLogger(t, log.ErrorLevel)
+
+		err := Err(Unauthorized, "denied", nil).With("token", "abc")
+		before := err.Error()
+
+		Convey("When a policy denying the key is applied and the error logged", func() {
+			setTestRedactionPolicy(t, RedactionPolicy{Keys: []string{"token"}})
+			Error(err)
+
+			Convey("Then neither the text nor the log line should keep the cached value", func() {
+				So(before, ShouldEqual, "denied token=abc")
+				So(err.Error(), ShouldEqual, "denied token=[REDACTED]")
+				So(buffer.String(), ShouldNotContainSubstring, "abc")
+			})
+		})
+	})
+
+	Convey("Given a Config with redaction settings", t, func() {
+		setTestRedactionPolicy(t, RedactionPolicy{})
+		configureTestLogger(t, log.InfoLevel)
+
+		cfg := &Config{Level: "info"}
+		cfg.Redaction.Keys = []string{"session"}
+		cfg.Redaction.Patterns = []string{`\d{4}-\d{4}-\d{4}-\d{4}`, `(`}
+		cfg.Redaction.Mask = "<hidden>"
+
+		Convey("When Apply is called", func() {
+			Apply(cfg)
+
+			Convey("Then the compiled policy should be active and invalid patterns skipped", func() {
+				So(Redact("session", "abc"), ShouldEqual, "<hidden>")
+				So(Redact("card", "card 1234-5678-9012-3456 declined"), ShouldEqual, "card <hidden> declined")
+				So(redactionPolicySnapshot().Patterns, ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkRedactFields measures field redaction on the logging hot path.
+*/
+func BenchmarkRedactFields(b *testing.B) {
+	fields := []any{"user", "ernie", "attempt", 2, "path", "/users/42"}
+
+	b.Run("no policy", func(b *testing.B) {
+		for range b.N {
+			benchmarkRedactSink = redactFields(fields)
+		}
+	})
+
+	b.Run("policy without matches", func(b *testing.B) {
+		setTestRedactionPolicy(b, RedactionPolicy{
+			Keys:     []string{"password", "token"},
+			Patterns: []*regexp.Regexp{regexp.MustCompile(`Bearer \S+`)},
+		})
+
+		b.ResetTimer()
+		for range b.N {
+			benchmarkRedactSink = redactFields(fields)
+		}
+	})
+}
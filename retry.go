@@ -0,0 +1,174 @@
+package errnie
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+/*
+RetryPolicy configures Retry. Zero MaxAttempts, InitialDelay, and Multiplier
+fall back to DefaultRetryPolicy; zero MaxDelay, Jitter, and MaxElapsed disable
+the cap, the randomization, and the time budget respectively.
+*/
+type RetryPolicy struct {
+	// MaxAttempts bounds the total number of calls, including the first.
+	MaxAttempts int
+	// InitialDelay is the wait before the second attempt.
+	InitialDelay time.Duration
+	// MaxDelay caps the exponential delay.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each failed attempt.
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction (0 to 1).
+	Jitter float64
+	// MaxElapsed stops retrying once the next wait would exceed this budget.
+	MaxElapsed time.Duration
+	// Retryable decides whether a failure is transient. Defaults to IsRetryable.
+	Retryable func(error) bool
+}
+
+/*
+DefaultRetryPolicy returns a policy with three attempts, exponential backoff
+from 100ms capped at 5s, and 20% jitter.
+*/
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.2,
+	}
+}
+
+/*
+IsRetryable reports whether err is transient according to the Kind registry.
+Context cancellation and errors without an ErrnieError are never retryable.
+*/
+func IsRetryable(err error) bool {
+	if err == nil || IsContext(err) {
+		return false
+	}
+
+	target, ok := AsErrnie(err)
+	if !ok {
+		return false
+	}
+
+	info, ok := LookupKind(target.Kind)
+
+	return ok && info.Retryable
+}
+
+/*
+Retry calls fn until it succeeds, returns a non-retryable error, runs out of
+attempts or elapsed time, or ctx is cancelled. Only errors whose Kind is
+registered as retryable (Timeout, ServiceUnavailable, TooManyRequests,
+BadGateway, IO, ...) are retried, and cancellation reported by IsContext stops
+immediately.
+
+On failure the Result's error is an ErrnieError with the last attempt's Kind,
+Op "retry", a cause that joins every attempt's failure with Combine, and the
+fields "attempts" and "stop" (exhausted, non_retryable, elapsed, or context).
+*/
+func Retry[T any](ctx context.Context, fn func(context.Context) (T, error), policy RetryPolicy) Result[T] {
+	policy = policy.withDefaults()
+	start := time.Now()
+
+	var failures []error
+
+	for attempt := 1; ; attempt++ {
+		value, err := fn(ctx)
+		if err == nil {
+			return Result[T]{value: value}
+		}
+
+		failures = append(failures, err)
+
+		switch {
+		case IsContext(err):
+			return Result[T]{value: value, err: retryError(failures, attempt, "context")}
+		case ctx.Err() != nil:
+			failures = append(failures, ctx.Err())
+
+			return Result[T]{value: value, err: retryError(failures, attempt, "context")}
+		case !policy.Retryable(err):
+			return Result[T]{value: value, err: retryError(failures, attempt, "non_retryable")}
+		case attempt >= policy.MaxAttempts:
+			return Result[T]{value: value, err: retryError(failures, attempt, "exhausted")}
+		}
+
+		wait := policy.delay(attempt)
+
+		if policy.MaxElapsed > 0 && time.Since(start)+wait > policy.MaxElapsed {
+			return Result[T]{value: value, err: retryError(failures, attempt, "elapsed")}
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			failures = append(failures, ctx.Err())
+
+			return Result[T]{value: value, err: retryError(failures, attempt, "context")}
+		case <-timer.C:
+		}
+	}
+}
+
+/*
+withDefaults fills the zero fields that have no meaningful zero behaviour.
+*/
+func (policy RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = defaults.InitialDelay
+	}
+
+	if policy.Multiplier < 1 {
+		policy.Multiplier = defaults.Multiplier
+	}
+
+	if policy.Retryable == nil {
+		policy.Retryable = IsRetryable
+	}
+
+	return policy
+}
+
+/*
+delay returns the jittered backoff to wait after the given failed attempt.
+*/
+func (policy RetryPolicy) delay(attempt int) time.Duration {
+	delay := float64(policy.InitialDelay) * math.Pow(policy.Multiplier, float64(attempt-1))
+
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+
+	if policy.Jitter > 0 {
+		delay -= delay * min(policy.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+/*
+retryError builds the terminal Retry error from every attempt's failure.
+*/
+func retryError(failures []error, attempts int, stop string) *ErrnieError {
+	return Err(
+		kindOf(failures[len(failures)-1]),
+		"gave up after "+strconv.Itoa(attempts)+" attempts",
+		Combine(failures...),
+	).Operation("retry").With("attempts", attempts, "stop", stop)
+}
@@ -0,0 +1,221 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+testRetryPolicy keeps retry tests fast.
+*/
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     2 * time.Millisecond,
+		Jitter:       0.5,
+	}
+}
+
+/*
+TestRetry verifies Kind-driven retrying, stop reasons, and error aggregation.
+*/
+func TestRetry(t *testing.T) {
+	Convey("Given a function that fails transiently before succeeding", t, func() {
+		calls := 0
+		fn := func(context.Context) (string, error) {
+			calls++
+
+			if calls < 3 {
+				return "", Err(ServiceUnavailable, "db down", nil)
+			}
+
+			return "ok", nil
+		}
+
+		Convey("When Retry is called", func() {
+			result := Retry(context.Background(), fn, testRetryPolicy())
+
+			Convey("Then it should return the eventual value", func() {
+				So(result.Err(), ShouldBeNil)
+				So(result.Value(), ShouldEqual, "ok")
+				So(calls, ShouldEqual, 3)
+			})
+		})
+	})
+
+	Convey("Given a function that always fails transiently", t, func() {
+		calls := 0
+		fn := func(context.Context) (int, error) {
+			calls++
+			return 0, Err(Timeout, "slow upstream", nil)
+		}
+
+		Convey("When Retry exhausts its attempts", func() {
+			result := Retry(context.Background(), fn, testRetryPolicy())
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then the error should join every attempt and record counts", func() {
+				So(calls, ShouldEqual, 3)
+				So(IsTimeout(result.Err()), ShouldBeTrue)
+				So(target.Op, ShouldEqual, "retry")
+				So(target.Fields(), ShouldResemble, []any{"attempts", 3, "stop", "exhausted"})
+
+				joined, ok := target.Cause.(interface{ Unwrap() []error })
+				So(ok, ShouldBeTrue)
+				So(joined.Unwrap(), ShouldHaveLength, 3)
+			})
+		})
+	})
+
+	Convey("Given a function that fails with a non-retryable Kind", t, func() {
+		calls := 0
+		fn := func(context.Context) (int, error) {
+			calls++
+			return 0, Err(Validation, "bad input", nil)
+		}
+
+		Convey("When Retry is called", func() {
+			result := Retry(context.Background(), fn, testRetryPolicy())
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then it should stop after the first attempt", func() {
+				So(calls, ShouldEqual, 1)
+				So(IsValidation(result.Err()), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"attempts", 1, "stop", "non_retryable"})
+			})
+		})
+	})
+
+	Convey("Given a function that reports cancellation", t, func() {
+		calls := 0
+		fn := func(context.Context) (int, error) {
+			calls++
+			return 0, Err(IO, "read aborted", context.Canceled)
+		}
+
+		Convey("When Retry is called", func() {
+			result := Retry(context.Background(), fn, testRetryPolicy())
+
+			Convey("Then it should stop immediately even though IO is retryable", func() {
+				So(calls, ShouldEqual, 1)
+				So(IsContext(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a context cancelled during a failed attempt", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		policy := testRetryPolicy()
+		policy.InitialDelay = time.Hour
+		policy.MaxDelay = 0
+		fn := func(context.Context) (int, error) {
+			cancel()
+			return 0, Err(BadGateway, "upstream 502", nil)
+		}
+
+		Convey("When Retry is called", func() {
+			result := Retry(ctx, fn, policy)
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then it should return the context failure without waiting", func() {
+				So(target.Cause.Error(), ShouldContainSubstring, "upstream 502")
+				So(IsCanceled(result.Err()), ShouldBeTrue)
+				So(errors.Is(result.Err(), context.Canceled), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"attempts", 1, "stop", "context"})
+			})
+		})
+	})
+
+	Convey("Given a context cancelled while waiting between attempts", t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		policy := testRetryPolicy()
+		policy.InitialDelay = time.Hour
+		policy.MaxDelay = 0
+		fn := func(context.Context) (int, error) {
+			return 0, Err(BadGateway, "upstream 502", nil)
+		}
+
+		Convey("When Retry is called", func() {
+			result := Retry(ctx, fn, policy)
+
+			Convey("Then it should wake up and report the deadline", func() {
+				So(IsDeadlineExceeded(result.Err()), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given an elapsed-time budget shorter than the backoff", t, func() {
+		policy := testRetryPolicy()
+		policy.InitialDelay = time.Second
+		policy.MaxDelay = 0
+		policy.MaxElapsed = 10 * time.Millisecond
+		fn := func(context.Context) (int, error) {
+			return 0, Err(TooManyRequests, "slow down", nil)
+		}
+
+		Convey("When Retry is called", func() {
+			result := Retry(context.Background(), fn, policy)
+			target, _ := AsErrnie(result.Err())
+
+			Convey("Then it should stop on the budget", func() {
+				So(IsTooManyRequests(result.Err()), ShouldBeTrue)
+				So(target.Fields(), ShouldResemble, []any{"attempts", 1, "stop", "elapsed"})
+			})
+		})
+	})
+}
+
+/*
+TestIsRetryable verifies retryability is read from the Kind registry.
+*/
+func TestIsRetryable(t *testing.T) {
+	Convey("Given errors of various Kinds", t, func() {
+		Convey("When IsRetryable is called", func() {
+			Convey("Then only transient Kinds should be retryable", func() {
+				So(IsRetryable(Err(ServiceUnavailable, "down", nil)), ShouldBeTrue)
+				So(IsRetryable(Err(NotFound, "missing", nil)), ShouldBeFalse)
+				So(IsRetryable(errors.New("plain")), ShouldBeFalse)
+				So(IsRetryable(Err(Timeout, "slow", context.DeadlineExceeded)), ShouldBeFalse)
+				So(IsRetryable(nil), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+/*
+TestRetryPolicyDelay verifies exponential growth, capping, and jitter bounds.
+*/
+func TestRetryPolicyDelay(t *testing.T) {
+	Convey("Given a policy without jitter", t, func() {
+		policy := RetryPolicy{InitialDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}.withDefaults()
+
+		Convey("When delays are computed", func() {
+			Convey("Then they should double and cap", func() {
+				So(policy.delay(1), ShouldEqual, 10*time.Millisecond)
+				So(policy.delay(2), ShouldEqual, 20*time.Millisecond)
+				So(policy.delay(3), ShouldEqual, 40*time.Millisecond)
+				So(policy.delay(4), ShouldEqual, 50*time.Millisecond)
+			})
+		})
+	})
+
+	Convey("Given a policy with full jitter", t, func() {
+		policy := RetryPolicy{InitialDelay: 10 * time.Millisecond, Jitter: 1}.withDefaults()
+
+		Convey("When delays are computed", func() {
+			Convey("Then they should stay within the undithered delay", func() {
+				for range 100 {
+					delay := policy.delay(1)
+					So(delay, ShouldBeBetweenOrEqual, 0, 10*time.Millisecond)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,263 @@
+package errnie
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/phuslu/log"
+)
+
+/*
+SlogHandler is a log/slog Handler that writes through the errnie logger, so
+libraries that log with slog reach the same sinks, level, and suppression as
+Error, Warn, and Info. The logger is read on every record, which keeps the
+handler in step with later Apply calls. Groups are flattened into dotted keys
+("request.id"), which Elasticsearch indexes as nested objects.
+*/
+type SlogHandler struct {
+	controller *LogController
+	prefix     string
+	attrs      []slogAttr
+}
+
+/*
+slogAttr is an attribute bound through WithAttrs together with the group
+prefix that was open at the time.
+*/
+type slogAttr struct {
+	prefix string
+	attr   slog.Attr
+}
+
+/*
+NewSlogHandler creates a SlogHandler. Records are dropped while SuppressLogging
+is active and, when controller is non-nil, while controller is suppressed.
+
+	slog.SetDefault(slog.New(errnie.NewSlogHandler(nil)))
+*/
+func NewSlogHandler(controller *LogController) *SlogHandler {
+	return &SlogHandler{controller: controller}
+}
+
+/*
+Enabled reports whether a record at level would be written under the current
+errnie level and suppression state.
+*/
+func (handler *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if loggingSuppressed() || handler.controller.Suppressed() {
+		return false
+	}
+
+	return slogLevel(level) >= logger.handle.Level
+}
+
+/*
+Handle writes record through the errnie logger. The caller field comes from
+the record's PC rather than the stack depth, because slog adds its own frames.
+*/
+func (handler *SlogHandler) Handle(_ context.Context, record slog.Record) error {
+	if loggingSuppressed() || handler.controller.Suppressed() {
+		return nil
+	}
+
+	handle := *logger.handle
+	handle.Caller = 0
+
+	entry := handle.WithLevel(slogLevel(record.Level))
+	if entry == nil {
+		return nil
+	}
+
+	if logger.handle.Caller != 0 && record.PC != 0 {
+		entry = entry.Str(log.CallerKey, slogCaller(record.PC, logger.handle.Caller < 0))
+	}
+
+	for _, bound := range handler.attrs {
+		entry = appendSlogAttr(entry, bound.prefix, bound.attr)
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		entry = appendSlogAttr(entry, handler.prefix, attr)
+		return true
+	})
+
+	entry.Msg(record.Message)
+
+	return nil
+}
+
+/*
+WithAttrs returns a handler that adds attrs to every record.
+*/
+func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return handler
+	}
+
+	derived := *handler
+	derived.attrs = make([]slogAttr, len(handler.attrs), len(handler.attrs)+len(attrs))
+	copy(derived.attrs, handler.attrs)
+
+	for _, attr := range attrs {
+		derived.attrs = append(derived.attrs, slogAttr{prefix: handler.prefix, attr: attr})
+	}
+
+	return &derived
+}
+
+/*
+WithGroup returns a handler that qualifies the keys of later attributes with
+name.
+*/
+func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+
+	derived := *handler
+	derived.prefix = handler.prefix + name + "."
+
+	return &derived
+}
+
+/*
+LogValue implements slog.LogValuer, so an ErrnieError passed to a slog call is
+logged as a group of kind, code, op, message, message_key, fields, and cause
+instead of a flat string.
+*/
+func (err *ErrnieError) LogValue() slog.Value {
+	if err == nil {
+		return slog.GroupValue()
+	}
+
+	attrs := make([]slog.Attr, 0, 7)
+	attrs = append(attrs, slog.String("kind", KindName(err.Kind)))
+
+	if err.Code != "" {
+		attrs = append(attrs, slog.String("code", string(err.Code)))
+	}
+
+	if err.Op != "" {
+		attrs = append(attrs, slog.String("op", err.Op))
+	}
+
+	attrs = append(attrs, slog.String("message", err.message()))
+
+	if err.MessageKey != "" {
+		attrs = append(attrs, slog.String("message_key", err.MessageKey))
+	}
+
+	if fields := err.Fields(); len(fields) > 0 {
+		attrs = append(attrs, slog.Group("fields", redactFields(fields)...))
+	}
+
+	if err.Cause != nil {
+		attrs = append(attrs, slog.String("cause", err.Cause.Error()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+/*
+appendSlogAttr writes attr to entry, flattening groups into prefixed keys and
+applying the redaction policy to leaf attributes.
+*/
+func appendSlogAttr(entry *log.Entry, prefix string, attr slog.Attr) *log.Entry {
+	if attr.Equal(slog.Attr{}) {
+		return entry
+	}
+
+	value := attr.Value.Resolve()
+	key := prefix + attr.Key
+
+	if policy := redactionPolicySnapshot(); policy != nil && value.Kind() != slog.KindGroup {
+		if policy.deniesKey(attr.Key) {
+			return entry.Str(key, policy.mask())
+		}
+
+		switch value.Kind() {
+		case slog.KindString:
+			if redacted, changed := policy.redactString(value.String()); changed {
+				return entry.Str(key, redacted)
+			}
+		case slog.KindAny:
+			if redacted, changed := policy.redact(attr.Key, value.Any()); changed {
+				return entry.Str(key, fmt.Sprint(redacted))
+			}
+		}
+	}
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		if attr.Key != "" {
+			key += "."
+		}
+
+		for _, child := range value.Group() {
+			entry = appendSlogAttr(entry, key, child)
+		}
+
+		return entry
+	case slog.KindString:
+		return entry.Str(key, value.String())
+	case slog.KindInt64:
+		return entry.Int64(key, value.Int64())
+	case slog.KindUint64:
+		return entry.Uint64(key, value.Uint64())
+	case slog.KindFloat64:
+		return entry.Float64(key, value.Float64())
+	case slog.KindBool:
+		return entry.Bool(key, value.Bool())
+	case slog.KindDuration:
+		return entry.Dur(key, value.Duration())
+	case slog.KindTime:
+		return entry.Time(key, value.Time())
+	default:
+		if valueErr, ok := value.Any().(error); ok {
+			return entry.Str(key, valueErr.Error())
+		}
+
+		return entry.Any(key, value.Any())
+	}
+}
+
+/*
+slogLevel maps a slog level onto the nearest phuslu/log level at or below it.
+*/
+func slogLevel(level slog.Level) log.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return log.TraceLevel
+	case level < slog.LevelInfo:
+		return log.DebugLevel
+	case level < slog.LevelWarn:
+		return log.InfoLevel
+	case level < slog.LevelError:
+		return log.WarnLevel
+	default:
+		return log.ErrorLevel
+	}
+}
+
+/*
+slogCaller renders pc as file:line, trimmed to the parent directory and file
+like the caller field errnie's own log calls produce unless full is set.
+*/
+func slogCaller(pc uintptr, full bool) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	file := frame.File
+
+	if !full {
+		if index := strings.LastIndexByte(file, '/'); index > 0 {
+			if parent := strings.LastIndexByte(file[:index], '/'); parent >= 0 {
+				file = file[parent+1:]
+			}
+		}
+	}
+
+	return file + ":" + strconv.Itoa(frame.Line)
+}
@@ -0,0 +1,167 @@
+package errnie
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+TestSlogHandler verifies that slog records are routed through the errnie
+logger.
+*/
+func TestSlogHandler(t *testing.T) {
+	Convey("Given a slog.Logger backed by a SlogHandler", t, func() {
+		buffer := configureTestLogger(t, log.InfoLevel)
+		logger := slog.New(NewSlogHandler(nil))
+
+		Convey("When an info record with attributes is logged", func() {
+			logger.Info("cache warmed", "entries", 42, "took", time.Second, "hot", true)
+
+			Convey("Then the line should carry the message, level, caller, and attributes", func() {
+				line := buffer.String()
+				So(line, ShouldContainSubstring, `"level":"info"`)
+				So(line, ShouldContainSubstring, `"message":"cache warmed"`)
+				So(line, ShouldContainSubstring, `"entries":42`)
+				So(line, ShouldContainSubstring, `"hot":true`)
+				So(line, ShouldContainSubstring, `/slog_test.go:`)
+			})
+		})
+
+		Convey("When a record is below the errnie level", func() {
+			logger.Debug("noise", "key", "value")
+
+			Convey("Then nothing should be written", func() {
+				So(buffer.Len(), ShouldEqual, 0)
+				So(logger.Enabled(context.Background(), slog.LevelDebug), ShouldBeFalse)
+				So(logger.Enabled(context.Background(), slog.LevelWarn), ShouldBeTrue)
+			})
+		})
+
+		Convey("When attributes and groups are bound with With and WithGroup", func() {
+			logger.With("service", "billing").WithGroup("request").With("id", "r-1").Warn(
+				"slow request",
+				slog.Group("timing", slog.Int("ms", 900)),
+			)
+
+			Convey("Then group keys should be flattened with dots", func() {
+				line := buffer.String()
+				So(line, ShouldContainSubstring, `"level":"warn"`)
+				So(line, ShouldContainSubstring, `"service":"billing"`)
+				So(line, ShouldContainSubstring, `"request.id":"r-1"`)
+				So(line, ShouldContainSubstring, `"request.timing.ms":900`)
+			})
+		})
+
+		Convey("When logging is suppressed globally", func() {
+			restore := SuppressLogging()
+			defer restore()
+
+			logger.Error("hidden")
+
+			Convey("Then nothing should be written", func() {
+				So(buffer.Len(), ShouldEqual, 0)
+				So(logger.Enabled(context.Background(), slog.LevelError), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a SlogHandler with its own LogController", t, func() {
+		buffer := configureTestLogger(t, log.InfoLevel)
+		controller := &LogController{}
+		logger := slog.New(NewSlogHandler(controller))
+
+		Convey("When the controller is suppressed", func() {
+			restore := controller.Suppress()
+			logger.Info("hidden")
+			restore()
+			logger.Info("visible")
+
+			Convey("Then only records after restore should be written", func() {
+				So(buffer.String(), ShouldNotContainSubstring, "hidden")
+				So(buffer.String(), ShouldContainSubstring, "visible")
+			})
+		})
+	})
+
+	Convey("Given slog levels between the named ones", t, func() {
+		Convey("When they are mapped", func() {
+			Convey("Then each should round down to the nearest errnie level", func() {
+				So(slogLevel(slog.LevelDebug-4), ShouldEqual, log.TraceLevel)
+				So(slogLevel(slog.LevelDebug), ShouldEqual, log.DebugLevel)
+				So(slogLevel(slog.LevelInfo+2), ShouldEqual, log.InfoLevel)
+				So(slogLevel(slog.LevelWarn), ShouldEqual, log.WarnLevel)
+				So(slogLevel(slog.LevelError+4), ShouldEqual, log.ErrorLevel)
+			})
+		})
+	})
+}
+
+/*
+TestErrnieErrorLogValue verifies structured slog output for ErrnieError.
+*/
+func TestErrnieErrorLogValue(t *testing.T) {
+	Convey("Given an enriched ErrnieError", t, func() {
+		err := Err(NotFound, "user missing", errors.New("no rows")).Operation("user.load").With("user_id", 7)
+
+		Convey("When it is resolved as a slog value", func() {
+			value := slog.AnyValue(err).Resolve()
+
+			Convey("Then it should be a group of kind, op, message, fields, and cause", func() {
+				So(value.Kind(), ShouldEqual, slog.KindGroup)
+				So(value.String(), ShouldEqual, "[kind=not_found op=user.load message=user missing fields=[user_id=7] cause=no rows]")
+			})
+		})
+
+		Convey("When it is logged through a SlogHandler", func() {
+			buffer := configureTestLogger(t, log.InfoLevel)
+			slog.New(NewSlogHandler(nil)).Error("lookup failed", "err", err)
+
+			Convey("Then its parts should be separate fields", func() {
+				line := buffer.String()
+				So(line, ShouldContainSubstring, `"err.kind":"not_found"`)
+				So(line, ShouldContainSubstring, `"err.op":"user.load"`)
+				So(line, ShouldContainSubstring, `"err.fields.user_id":7`)
+				So(line, ShouldContainSubstring, `"err.cause":"no rows"`)
+			})
+		})
+	})
+
+	Convey("Given a nil ErrnieError", t, func() {
+		var err *ErrnieError
+
+		Convey("When it is resolved as a slog value", func() {
+			value := err.LogValue()
+
+			Convey("Then it should be an empty group", func() {
+				So(value.Kind(), ShouldEqual, slog.KindGroup)
+				So(value.Group(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+/*
+BenchmarkSlogHandler measures a slog call routed through the errnie logger.
+*/
+func BenchmarkSlogHandler(b *testing.B) {
+	configureBenchmarkLogger(b, log.InfoLevel)
+	logger := slog.New(NewSlogHandler(nil))
+
+	b.Run("enabled", func(b *testing.B) {
+		for range b.N {
+			logger.Info("request served", "status", 200, "path", "/users")
+		}
+	})
+
+	b.Run("disabled", func(b *testing.B) {
+		for range b.N {
+			logger.Debug("request served", "status", 200, "path", "/users")
+		}
+	})
+}
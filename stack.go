@@ -0,0 +1,126 @@
+package errnie
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+stackDepth bounds the number of program counters recorded per ErrnieError.
+*/
+const stackDepth = 32
+
+/*
+stackTraces is the package-level switch for stack capture. Apply sets it from
+Config.StackTraces; the read in Err and Guard is a single atomic load so the
+disabled path stays allocation-free.
+*/
+var stackTraces atomic.Bool
+
+/*
+stackTrace holds the raw program counters recorded when an ErrnieError was
+constructed. Symbolization is deferred until the trace is logged or formatted.
+*/
+type stackTrace struct {
+	pcs []uintptr
+}
+
+/*
+captureStack records the call stack above its caller. skip is the number of
+frames to drop beyond captureStack itself, so skip 0 starts at the caller.
+*/
+func captureStack(skip int) *stackTrace {
+	var pcs [stackDepth]uintptr
+
+	count := runtime.Callers(skip+2, pcs[:])
+
+	if count == 0 {
+		return nil
+	}
+
+	return &stackTrace{pcs: append([]uintptr(nil), pcs[:count]...)}
+}
+
+/*
+Frames symbolizes the recorded program counters.
+*/
+func (trace *stackTrace) Frames() []runtime.Frame {
+	if trace == nil || len(trace.pcs) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(trace.pcs)
+	out := make([]runtime.Frame, 0, len(trace.pcs))
+
+	for {
+		frame, more := frames.Next()
+		out = append(out, frame)
+
+		if !more {
+			break
+		}
+	}
+
+	return out
+}
+
+/*
+String renders the trace one frame per function/location pair, matching the
+layout of runtime/debug.Stack.
+*/
+func (trace *stackTrace) String() string {
+	var builder strings.Builder
+
+	for index, frame := range trace.Frames() {
+		if index > 0 {
+			builder.WriteByte('\n')
+		}
+
+		builder.WriteString(frame.Function)
+		builder.WriteString("\n\t")
+		builder.WriteString(frame.File)
+		builder.WriteByte(':')
+		builder.WriteString(strconv.Itoa(frame.Line))
+	}
+
+	return builder.String()
+}
+
+/*
+stackOf returns the first stack trace found while walking err's chain, or nil
+when no ErrnieError in the chain carries one.
+*/
+func stackOf(err error) *stackTrace {
+	for err != nil {
+		if target, ok := err.(*ErrnieError); ok {
+			if target == nil {
+				return nil
+			}
+
+			if target.stack != nil {
+				return target.stack
+			}
+
+			err = target.Cause
+
+			continue
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, child := range joined.Unwrap() {
+				if trace := stackOf(child); trace != nil {
+					return trace
+				}
+			}
+
+			return nil
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package errnie
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/phuslu/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+enableTestStackTraces turns stack capture on for the duration of a test.
+*/
+func enableTestStackTraces(t *testing.T) {
+	t.Helper()
+
+	previous := stackTraces.Load()
+	stackTraces.Store(true)
+
+	t.Cleanup(func() {
+		stackTraces.Store(previous)
+	})
+}
+
+/*
+TestStackTrace verifies opt-in stack capture on Err and Guard.
+*/
+func TestStackTrace(t *testing.T) {
+	Convey("Given stack traces are disabled", t, func() {
+		Convey("When Err is called", func() {
+			err := Err(Internal, "boom", nil)
+
+			Convey("Then no trace should be recorded", func() {
+				So(err.StackTrace(), ShouldBeNil)
+				So(fmt.Sprintf("%+v", err), ShouldEqual, "[internal] boom")
+			})
+		})
+	})
+
+	Convey("Given stack traces are enabled", t, func() {
+		enableTestStackTraces(t)
+
+		Convey("When Err is called", func() {
+			err := Err(Internal, "boom", nil)
+			frames := err.StackTrace()
+
+			Convey("Then the first frame should be the caller of Err", func() {
+				So(frames, ShouldNotBeEmpty)
+				So(frames[0].Function, ShouldContainSubstring, "TestStackTrace")
+				So(frames[0].File, ShouldEndWith, "stack_test.go")
+			})
+		})
+
+		Convey("When Guard is called with a cause", func() {
+			err := Guard(IO, "read failed", errors.New("disk"))
+			target, _ := AsErrnie(err)
+
+			Convey("Then the first frame should be the caller of Guard", func() {
+				So(target.StackTrace()[0].Function, ShouldContainSubstring, "TestStackTrace")
+			})
+		})
+
+		Convey("When an error with a trace is wrapped", func() {
+			inner := Err(NotFound, "missing", nil)
+			outer := Err(Internal, "load failed", fmt.Errorf("wrap: %w", inner))
+
+			Convey("Then the outer error should reuse the inner trace", func() {
+				So(outer.stack, ShouldBeNil)
+				So(stackOf(outer), ShouldEqual, inner.stack)
+			})
+		})
+
+		Convey("When the error is formatted with %+v", func() {
+			err := Err(Internal, "boom", nil)
+			text := fmt.Sprintf("%+v", err)
+
+			Convey("Then the output should include the symbolized trace", func() {
+				So(text, ShouldStartWith, "[internal] boom\n  at ")
+				So(text, ShouldContainSubstring, "\n  stack:\n")
+				So(text, ShouldContainSubstring, "stack_test.go:")
+				So(fmt.Sprintf("%v", err), ShouldEqual, "boom")
+			})
+		})
+
+		Convey("When the error is logged through Error", func() {
+			buffer := configureTestLogger(t, log.ErrorLevel)
+			Error(Err(Internal, "boom", nil))
+
+			Convey("Then the log line should carry the stack field", func() {
+				So(buffer.String(), ShouldContainSubstring, `"stack":`)
+				So(buffer.String(), ShouldContainSubstring, "stack_test.go")
+			})
+		})
+	})
+}
+
+/*
+BenchmarkStackTrace measures Err with stack capture enabled.
+*/
+func BenchmarkStackTrace(b *testing.B) {
+	stackTraces.Store(true)
+	b.Cleanup(func() {
+		stackTraces.Store(false)
+	})
+
+	for range b.N {
+		benchmarkErrnieSink = Err(Internal, "boom", nil)
+	}
+}